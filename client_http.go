@@ -0,0 +1,151 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// httpTransportSettings represents the settings that control how the provider connects to the Octopus Deploy server.
+type httpTransportSettings struct {
+	// PEM-encoded CA certificates to trust (in addition to the system's CA certificates).
+	CACertificatesPEM []byte
+
+	// The paths of the files containing the client certificate and its private key (if any).
+	ClientCertificateFile string
+	ClientKeyFile         string
+
+	// Skip verification of the server's certificate?
+	InsecureSkipVerify bool
+
+	// The URL of the HTTP(S) proxy (nil to use the proxy environment variables).
+	ProxyURL *url.URL
+
+	// The timeout for each request (0 for no timeout).
+	Timeout time.Duration
+
+	// The policy for retrying requests that fail due to transient errors.
+	RetryPolicy retryPolicy
+}
+
+// Get the HTTP transport settings from the provider configuration.
+func getHTTPTransportSettings(providerSettings *schema.ResourceData) (settings httpTransportSettings, err error) {
+	caCertificateFile := providerSettings.Get("ca_certificate_file").(string)
+	caCertificatePEM := providerSettings.Get("ca_certificate_pem").(string)
+	if !isEmpty(caCertificateFile) && !isEmpty(caCertificatePEM) {
+		err = fmt.Errorf("Only one of the 'ca_certificate_file' or 'ca_certificate_pem' properties can be specified for the 'octopus' provider.")
+
+		return
+	}
+	if !isEmpty(caCertificateFile) {
+		settings.CACertificatesPEM, err = ioutil.ReadFile(caCertificateFile)
+		if err != nil {
+			err = fmt.Errorf("Unable to read CA certificates from '%s': %s", caCertificateFile, err.Error())
+
+			return
+		}
+	} else if !isEmpty(caCertificatePEM) {
+		settings.CACertificatesPEM = []byte(caCertificatePEM)
+	}
+
+	settings.ClientCertificateFile = providerSettings.Get("client_certificate_file").(string)
+	settings.ClientKeyFile = providerSettings.Get("client_key_file").(string)
+	if isEmpty(settings.ClientCertificateFile) != isEmpty(settings.ClientKeyFile) {
+		err = fmt.Errorf("The 'client_certificate_file' and 'client_key_file' properties must be specified together for the 'octopus' provider.")
+
+		return
+	}
+
+	settings.InsecureSkipVerify = providerSettings.Get("insecure_skip_verify").(bool)
+
+	proxyURL := providerSettings.Get("proxy_url").(string)
+	if !isEmpty(proxyURL) {
+		settings.ProxyURL, err = url.Parse(proxyURL)
+		if err != nil {
+			err = fmt.Errorf("Invalid value '%s' for the 'proxy_url' property of the 'octopus' provider: %s", proxyURL, err.Error())
+
+			return
+		}
+	}
+
+	timeout := providerSettings.Get("timeout").(int)
+	if timeout < 0 {
+		err = fmt.Errorf("Invalid value %d for the 'timeout' property of the 'octopus' provider (must be 0 or greater).", timeout)
+
+		return
+	}
+	settings.Timeout = time.Duration(timeout) * time.Second
+
+	settings.RetryPolicy.MaxAttempts = providerSettings.Get("retry_max_attempts").(int)
+	if settings.RetryPolicy.MaxAttempts < 1 {
+		err = fmt.Errorf("Invalid value %d for the 'retry_max_attempts' property of the 'octopus' provider (must be 1 or greater).", settings.RetryPolicy.MaxAttempts)
+
+		return
+	}
+	minBackoff := providerSettings.Get("retry_min_backoff").(int)
+	maxBackoff := providerSettings.Get("retry_max_backoff").(int)
+	if minBackoff < 0 || maxBackoff < minBackoff {
+		err = fmt.Errorf("Invalid values %d and %d for the 'retry_min_backoff' and 'retry_max_backoff' properties of the 'octopus' provider (must be 0 or greater, and retry_max_backoff must not be less than retry_min_backoff).", minBackoff, maxBackoff)
+
+		return
+	}
+	settings.RetryPolicy.MinBackoff = time.Duration(minBackoff) * time.Second
+	settings.RetryPolicy.MaxBackoff = time.Duration(maxBackoff) * time.Second
+	maxWait := providerSettings.Get("retry_max_wait").(int)
+	if maxWait < 0 {
+		err = fmt.Errorf("Invalid value %d for the 'retry_max_wait' property of the 'octopus' provider (must be 0 or greater).", maxWait)
+
+		return
+	}
+	settings.RetryPolicy.MaxRetryAfter = time.Duration(maxWait) * time.Second
+
+	return
+}
+
+// Create the base HTTP transport used to connect to the Octopus Deploy server.
+func newHTTPTransport(settings httpTransportSettings) (*http.Transport, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: settings.InsecureSkipVerify,
+	}
+
+	if len(settings.CACertificatesPEM) > 0 {
+		rootCAs, err := x509.SystemCertPool()
+		if err != nil || rootCAs == nil {
+			rootCAs = x509.NewCertPool()
+		}
+		if !rootCAs.AppendCertsFromPEM(settings.CACertificatesPEM) {
+			return nil, fmt.Errorf("No valid PEM-encoded CA certificates were found in the CA certificate bundle.")
+		}
+		tlsConfig.RootCAs = rootCAs
+	}
+
+	if !isEmpty(settings.ClientCertificateFile) {
+		clientCertificate, err := tls.LoadX509KeyPair(settings.ClientCertificateFile, settings.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to load client certificate from '%s' and '%s': %s", settings.ClientCertificateFile, settings.ClientKeyFile, err.Error())
+		}
+		tlsConfig.Certificates = []tls.Certificate{clientCertificate}
+	}
+
+	proxy := http.ProxyFromEnvironment
+	if settings.ProxyURL != nil {
+		proxy = http.ProxyURL(settings.ProxyURL)
+	}
+
+	return &http.Transport{
+		Proxy: proxy,
+		Dial: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).Dial,
+		TLSClientConfig:       tlsConfig,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}, nil
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// retryPolicy determines how failed requests to the Octopus Deploy API are retried.
+type retryPolicy struct {
+	// The maximum number of attempts (including the first) made for each request.
+	MaxAttempts int
+
+	// The delay before the first retry (subsequent delays double, up to MaxBackoff).
+	MinBackoff time.Duration
+
+	// The maximum delay between attempts (unless the server specifies a longer one via Retry-After).
+	MaxBackoff time.Duration
+
+	// The maximum delay that will be honoured when the server specifies one via Retry-After.
+	MaxRetryAfter time.Duration
+}
+
+// Backoff calculates the delay before the specified retry (1 for the first retry).
+func (policy retryPolicy) Backoff(retry int) time.Duration {
+	backoff := policy.MinBackoff
+	for index := 1; index < retry && backoff < policy.MaxBackoff; index++ {
+		backoff *= 2
+	}
+	if backoff > policy.MaxBackoff {
+		backoff = policy.MaxBackoff
+	}
+
+	return backoff
+}
+
+// retryTransport is an http.RoundTripper that retries requests that fail due to transient errors.
+//
+// Requests are retried if the server responds with 429 (Too Many Requests), or, for idempotent requests only, if the server responds with 502, 503, or 504.
+// Requests that fail without a response are only retried if they are read-only (see isSafeToResend).
+type retryTransport struct {
+	// The retry policy.
+	policy retryPolicy
+
+	// The underlying transport.
+	next http.RoundTripper
+
+	// Wait for the specified delay (or until the request is cancelled); returns false if the request was cancelled.
+	wait func(request *http.Request, delay time.Duration) bool
+}
+
+func newRetryTransport(policy retryPolicy, next http.RoundTripper) *retryTransport {
+	return &retryTransport{
+		policy: policy,
+		next:   next,
+		wait:   waitForRetry,
+	}
+}
+
+// RoundTrip executes a single HTTP transaction (retrying it if necessary).
+func (transport *retryTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	if transport.policy.MaxAttempts <= 1 {
+		return transport.next.RoundTrip(request)
+	}
+
+	// Buffer the request body so that it can be re-sent.
+	var body []byte
+	if request.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(request.Body)
+		request.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for attempt := 1; ; attempt++ {
+		// RoundTrippers must not modify the original request.
+		attemptRequest := new(http.Request)
+		*attemptRequest = *request
+		if body != nil {
+			attemptRequest.Body = ioutil.NopCloser(bytes.NewReader(body))
+		}
+
+		response, err := transport.next.RoundTrip(attemptRequest)
+		if attempt >= transport.policy.MaxAttempts || !shouldRetry(request, response, err) {
+			return response, err
+		}
+
+		delay := transport.policy.Backoff(attempt)
+		if response != nil {
+			retryAfter, ok := parseRetryAfter(response.Header.Get("Retry-After"), time.Now())
+			if ok && retryAfter > transport.policy.MaxRetryAfter {
+				log.Printf("[WARN] %s %s requested a retry delay of %s; using the maximum of %s instead.", request.Method, request.URL, retryAfter, transport.policy.MaxRetryAfter)
+
+				retryAfter = transport.policy.MaxRetryAfter
+			}
+			if ok && retryAfter > delay {
+				delay = retryAfter
+			}
+
+			log.Printf("[WARN] %s %s returned %s (attempt %d of %d); retrying in %s.", request.Method, request.URL, response.Status, attempt, transport.policy.MaxAttempts, delay)
+
+			// Discard the response so its connection can be reused.
+			io.Copy(ioutil.Discard, response.Body)
+			response.Body.Close()
+		} else {
+			log.Printf("[WARN] %s %s failed (attempt %d of %d); retrying in %s: %s", request.Method, request.URL, attempt, transport.policy.MaxAttempts, delay, err.Error())
+		}
+
+		if !transport.wait(request, delay) {
+			return nil, request.Context().Err()
+		}
+	}
+}
+
+// Determine whether a request should be retried, based on its outcome.
+func shouldRetry(request *http.Request, response *http.Response, err error) bool {
+	if err != nil {
+		// The request may or may not have reached the server.
+		return isSafeToResend(request.Method) && request.Context().Err() == nil
+	}
+
+	switch response.StatusCode {
+	case http.StatusTooManyRequests:
+		// The server has not processed the request.
+		return true
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return isIdempotent(request.Method)
+	default:
+		return false
+	}
+}
+
+// Determine whether requests with the specified HTTP method can be repeated once the server has rejected them (e.g. with 503).
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// Determine whether requests with the specified HTTP method can be repeated when it is not known whether the server processed them.
+//
+// Although PUT and DELETE are idempotent, Octopus rejects a PUT whose Version no longer matches the resource (so repeating a PUT that was applied fails with 409) and repeating a DELETE that was applied fails with 404.
+func isSafeToResend(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	default:
+		return false
+	}
+}
+
+// Parse the value of a Retry-After header (either a number of seconds or an HTTP date).
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if isEmpty(value) {
+		return 0, false
+	}
+
+	seconds, err := strconv.Atoi(value)
+	if err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	retryAt, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	delay := retryAt.Sub(now)
+	if delay < 0 {
+		delay = 0
+	}
+
+	return delay, true
+}
+
+// Wait for the specified delay, unless the request is cancelled first.
+func waitForRetry(request *http.Request, delay time.Duration) bool {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-request.Context().Done():
+		return false
+	}
+}
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+var testRetryPolicy = retryPolicy{
+	MaxAttempts:   3,
+	MinBackoff:    1 * time.Second,
+	MaxBackoff:    30 * time.Second,
+	MaxRetryAfter: 60 * time.Second,
+}
+
+// testRetryServer responds to each request with the next status code in its list (repeating the last one once the list is exhausted).
+type testRetryServer struct {
+	*httptest.Server
+
+	stateLock   *sync.Mutex
+	statusCodes []int
+	retryAfter  string
+	bodies      []string
+}
+
+func newTestRetryServer(retryAfter string, statusCodes ...int) *testRetryServer {
+	server := &testRetryServer{
+		stateLock:   &sync.Mutex{},
+		statusCodes: statusCodes,
+		retryAfter:  retryAfter,
+	}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+		body, _ := ioutil.ReadAll(request.Body)
+
+		server.stateLock.Lock()
+		defer server.stateLock.Unlock()
+
+		statusCode := server.statusCodes[len(server.statusCodes)-1]
+		if len(server.bodies) < len(server.statusCodes) {
+			statusCode = server.statusCodes[len(server.bodies)]
+		}
+		server.bodies = append(server.bodies, string(body))
+
+		if statusCode != http.StatusOK && !isEmpty(server.retryAfter) {
+			response.Header().Set("Retry-After", server.retryAfter)
+		}
+		response.WriteHeader(statusCode)
+	}))
+
+	return server
+}
+
+// The bodies of the requests received by the server.
+func (server *testRetryServer) Bodies() []string {
+	server.stateLock.Lock()
+	defer server.stateLock.Unlock()
+
+	return append([]string(nil), server.bodies...)
+}
+
+// Create a retry transport that records its delays instead of waiting.
+func newTestRetryTransport(policy retryPolicy, next http.RoundTripper) (*retryTransport, *[]time.Duration) {
+	delays := &[]time.Duration{}
+
+	transport := newRetryTransport(policy, next)
+	transport.wait = func(request *http.Request, delay time.Duration) bool {
+		*delays = append(*delays, delay)
+
+		return true
+	}
+
+	return transport, delays
+}
+
+func sendTestRequest(t *testing.T, transport http.RoundTripper, method string, url string, body string) *http.Response {
+	request, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	response, err := transport.RoundTrip(request)
+	if err != nil {
+		t.Fatalf("%s %s failed: %s", method, url, err.Error())
+	}
+	response.Body.Close()
+
+	return response
+}
+
+func assertDelays(t *testing.T, actual []time.Duration, expected ...time.Duration) {
+	if len(actual) != len(expected) {
+		t.Fatalf("Expected delays %v, but found %v.", expected, actual)
+	}
+	for index := range expected {
+		if actual[index] != expected[index] {
+			t.Fatalf("Expected delays %v, but found %v.", expected, actual)
+		}
+	}
+}
+
+func TestRetryTransportRetriesIdempotentRequest(t *testing.T) {
+	server := newTestRetryServer("", http.StatusServiceUnavailable, http.StatusOK)
+	defer server.Close()
+
+	transport, delays := newTestRetryTransport(testRetryPolicy, http.DefaultTransport)
+	response := sendTestRequest(t, transport, http.MethodGet, server.URL, "")
+
+	if response.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status %d, but found %d.", http.StatusOK, response.StatusCode)
+	}
+	if len(server.Bodies()) != 2 {
+		t.Fatalf("Expected 2 attempts, but found %d.", len(server.Bodies()))
+	}
+	assertDelays(t, *delays, testRetryPolicy.MinBackoff)
+}
+
+func TestRetryTransportDoesNotRetryNonIdempotentRequest(t *testing.T) {
+	server := newTestRetryServer("", http.StatusServiceUnavailable, http.StatusOK)
+	defer server.Close()
+
+	transport, delays := newTestRetryTransport(testRetryPolicy, http.DefaultTransport)
+	response := sendTestRequest(t, transport, http.MethodPost, server.URL, "{}")
+
+	if response.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("Expected status %d, but found %d.", http.StatusServiceUnavailable, response.StatusCode)
+	}
+	if len(server.Bodies()) != 1 {
+		t.Fatalf("Expected 1 attempt, but found %d.", len(server.Bodies()))
+	}
+	assertDelays(t, *delays)
+}
+
+func TestRetryTransportHonoursRetryAfter(t *testing.T) {
+	server := newTestRetryServer("2", http.StatusTooManyRequests, http.StatusOK)
+	defer server.Close()
+
+	transport, delays := newTestRetryTransport(testRetryPolicy, http.DefaultTransport)
+	response := sendTestRequest(t, transport, http.MethodPost, server.URL, "{}")
+
+	if response.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status %d, but found %d.", http.StatusOK, response.StatusCode)
+	}
+	assertDelays(t, *delays, 2*time.Second)
+}
+
+func TestRetryTransportCapsRetryAfter(t *testing.T) {
+	server := newTestRetryServer("3600", http.StatusTooManyRequests, http.StatusOK)
+	defer server.Close()
+
+	transport, delays := newTestRetryTransport(testRetryPolicy, http.DefaultTransport)
+	sendTestRequest(t, transport, http.MethodGet, server.URL, "")
+
+	assertDelays(t, *delays, testRetryPolicy.MaxRetryAfter)
+}
+
+func TestRetryTransportRespectsMaxAttempts(t *testing.T) {
+	server := newTestRetryServer("", http.StatusServiceUnavailable)
+	defer server.Close()
+
+	transport, delays := newTestRetryTransport(testRetryPolicy, http.DefaultTransport)
+	response := sendTestRequest(t, transport, http.MethodGet, server.URL, "")
+
+	if response.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("Expected status %d, but found %d.", http.StatusServiceUnavailable, response.StatusCode)
+	}
+	if len(server.Bodies()) != testRetryPolicy.MaxAttempts {
+		t.Fatalf("Expected %d attempts, but found %d.", testRetryPolicy.MaxAttempts, len(server.Bodies()))
+	}
+	assertDelays(t, *delays, 1*time.Second, 2*time.Second)
+}
+
+func TestRetryTransportResendsRequestBody(t *testing.T) {
+	server := newTestRetryServer("", http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK)
+	defer server.Close()
+
+	body := `{"Id":"Environments-1","Name":"Test","Version":3}`
+
+	transport, _ := newTestRetryTransport(testRetryPolicy, http.DefaultTransport)
+	sendTestRequest(t, transport, http.MethodPut, server.URL, body)
+
+	bodies := server.Bodies()
+	if len(bodies) != 3 {
+		t.Fatalf("Expected 3 attempts, but found %d.", len(bodies))
+	}
+	for index, attemptBody := range bodies {
+		if attemptBody != body {
+			t.Fatalf("Attempt %d sent body '%s' (expected '%s').", index+1, attemptBody, body)
+		}
+	}
+}
+
+// failingTransport is an http.RoundTripper whose requests never reach the server.
+type failingTransport struct {
+	attempts int
+}
+
+func (transport *failingTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	transport.attempts++
+
+	return nil, errors.New("connection reset by peer")
+}
+
+func TestRetryTransportRetriesOnlyReadOnlyRequestsAfterTransportError(t *testing.T) {
+	testCases := []struct {
+		method           string
+		expectedAttempts int
+	}{
+		{http.MethodGet, testRetryPolicy.MaxAttempts},
+		{http.MethodPut, 1},
+		{http.MethodDelete, 1},
+		{http.MethodPost, 1},
+	}
+
+	for _, testCase := range testCases {
+		next := &failingTransport{}
+		transport, _ := newTestRetryTransport(testRetryPolicy, next)
+
+		request, err := http.NewRequest(testCase.method, "http://octopus.example.com/api/environments/Environments-1", strings.NewReader("{}"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = transport.RoundTrip(request)
+		if err == nil {
+			t.Fatalf("%s: expected an error.", testCase.method)
+		}
+		if next.attempts != testCase.expectedAttempts {
+			t.Fatalf("%s: expected %d attempts, but found %d.", testCase.method, testCase.expectedAttempts, next.attempts)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2019, time.March, 4, 10, 30, 0, 0, time.UTC)
+
+	testCases := []struct {
+		value         string
+		expectedDelay time.Duration
+		expectedOK    bool
+	}{
+		{"", 0, false},
+		{"2", 2 * time.Second, true},
+		{"0", 0, true},
+		{"-1", 0, false},
+		{"soon", 0, false},
+		{now.Add(90 * time.Second).Format(http.TimeFormat), 90 * time.Second, true},
+		{"Monday, 04-Mar-19 10:31:00 GMT", 60 * time.Second, true},
+		{now.Add(-time.Minute).Format(http.TimeFormat), 0, true},
+	}
+
+	for _, testCase := range testCases {
+		delay, ok := parseRetryAfter(testCase.value, now)
+		if delay != testCase.expectedDelay || ok != testCase.expectedOK {
+			t.Errorf("parseRetryAfter('%s') returned (%s, %t); expected (%s, %t).", testCase.value, delay, ok, testCase.expectedDelay, testCase.expectedOK)
+		}
+	}
+}
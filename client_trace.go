@@ -0,0 +1,182 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/hashicorp/terraform/helper/logging"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// The placeholder logged in place of redacted values.
+const redactedValue = "<redacted>"
+
+// The maximum number of bytes of each request / response body that will be logged.
+const maxTracedBodyLength = 64 * 1024
+
+// HTTP headers whose values are never logged.
+var redactedHeaders = map[string]bool{
+	"Authorization":    true,
+	"Cookie":           true,
+	"Set-Cookie":       true,
+	"X-Octopus-Apikey": true,
+}
+
+// JSON properties whose values are never logged.
+//
+// Property names are matched case-insensitively by suffix, so that (for example) "Octopus.Action.Azure.Password" and "ClientSecret" are also redacted.
+var redactedPropertySuffixes = []string{
+	"apikey",
+	"newvalue", // Octopus sensitive values ({"HasValue": true, "NewValue": "..."}).
+	"passphrase",
+	"password",
+	"privatekey",
+	"secret",
+	"token",
+}
+
+// traceTransport is an http.RoundTripper that logs each request and response (with credentials and sensitive values redacted).
+type traceTransport struct {
+	// The underlying transport.
+	next http.RoundTripper
+}
+
+// Wrap a transport so that requests and responses are traced if Terraform's log level (TF_LOG) is DEBUG or TRACE.
+func newTraceTransport(next http.RoundTripper) http.RoundTripper {
+	logLevel := logging.LogLevel()
+	if logLevel != "DEBUG" && logLevel != "TRACE" {
+		return next
+	}
+
+	return &traceTransport{
+		next: next,
+	}
+}
+
+// RoundTrip executes a single HTTP transaction.
+func (transport *traceTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	var requestBody []byte
+	if request.Body != nil {
+		var err error
+		requestBody, err = ioutil.ReadAll(request.Body)
+		request.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		request.Body = ioutil.NopCloser(bytes.NewReader(requestBody))
+	}
+
+	log.Printf("[DEBUG] Octopus API request: %s %s\n%s%s", request.Method, request.URL, formatTracedHeaders(request.Header), formatTracedBody(requestBody))
+
+	startTime := time.Now()
+	response, err := transport.next.RoundTrip(request)
+	elapsed := time.Since(startTime)
+	if err != nil {
+		log.Printf("[DEBUG] Octopus API request failed after %s: %s %s: %s", elapsed, request.Method, request.URL, err.Error())
+
+		return response, err
+	}
+
+	responseBody, err := ioutil.ReadAll(response.Body)
+	response.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	response.Body = ioutil.NopCloser(bytes.NewReader(responseBody))
+
+	log.Printf("[DEBUG] Octopus API response (after %s): %s %s: %s\n%s%s", elapsed, request.Method, request.URL, response.Status, formatTracedHeaders(response.Header), formatTracedBody(responseBody))
+
+	return response, nil
+}
+
+// Format HTTP headers for tracing (redacting credentials).
+func formatTracedHeaders(headers http.Header) string {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var formattedHeaders bytes.Buffer
+	for _, name := range names {
+		value := strings.Join(headers[name], ", ")
+		if redactedHeaders[http.CanonicalHeaderKey(name)] {
+			value = redactedValue
+		}
+		fmt.Fprintf(&formattedHeaders, "%s: %s\n", name, value)
+	}
+
+	return formattedHeaders.String()
+}
+
+// Format an HTTP request / response body for tracing (redacting sensitive values).
+func formatTracedBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	var document interface{}
+	err := json.Unmarshal(body, &document)
+	if err != nil {
+		// Not JSON; we can't tell what's sensitive, so only log its size.
+		return fmt.Sprintf("\n<%d bytes of non-JSON content>", len(body))
+	}
+
+	formattedBody, err := json.MarshalIndent(redactSensitiveValues(document), "", "  ")
+	if err != nil {
+		return fmt.Sprintf("\n<%d bytes of content>", len(body))
+	}
+	if len(formattedBody) > maxTracedBodyLength {
+		return fmt.Sprintf("\n%s\n<truncated; %d bytes in total>", formattedBody[:maxTracedBodyLength], len(formattedBody))
+	}
+
+	return "\n" + string(formattedBody)
+}
+
+// Redact sensitive values (e.g. the values of sensitive variables) from a JSON document.
+func redactSensitiveValues(document interface{}) interface{} {
+	switch value := document.(type) {
+	case map[string]interface{}:
+		// Octopus variables are sensitive if they are flagged as such, or have the Sensitive type.
+		isSensitiveVariable := value["IsSensitive"] == true || value["Type"] == variableTypeSensitive
+
+		for propertyName, propertyValue := range value {
+			if propertyValue == nil {
+				continue
+			}
+			if isRedactedProperty(propertyName) || (isSensitiveVariable && propertyName == "Value") {
+				value[propertyName] = redactedValue
+
+				continue
+			}
+			value[propertyName] = redactSensitiveValues(propertyValue)
+		}
+
+		return value
+	case []interface{}:
+		for index, element := range value {
+			value[index] = redactSensitiveValues(element)
+		}
+
+		return value
+	default:
+		return value
+	}
+}
+
+// Determine whether the value of a JSON property should never be logged.
+func isRedactedProperty(propertyName string) bool {
+	propertyName = strings.ToLower(propertyName)
+	for _, suffix := range redactedPropertySuffixes {
+		if strings.HasSuffix(propertyName, suffix) {
+			return true
+		}
+	}
+
+	return false
+}
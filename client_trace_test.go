@@ -0,0 +1,89 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestFormatTracedHeadersRedactsCredentials(t *testing.T) {
+	headers := http.Header{
+		"Accept":           []string{"application/json"},
+		"Authorization":    []string{"Bearer my-bearer-token"},
+		"X-Octopus-Apikey": []string{"API-MYAPIKEY"},
+	}
+	headers["x-octopus-apikey"] = []string{"API-LOWERCASEKEY"} // Not canonicalised.
+
+	formattedHeaders := formatTracedHeaders(headers)
+
+	for _, secret := range []string{"my-bearer-token", "API-MYAPIKEY", "API-LOWERCASEKEY"} {
+		if strings.Contains(formattedHeaders, secret) {
+			t.Fatalf("Expected '%s' to be redacted, but found:\n%s", secret, formattedHeaders)
+		}
+	}
+	if !strings.Contains(formattedHeaders, "Accept: application/json") {
+		t.Fatalf("Expected the Accept header to be logged, but found:\n%s", formattedHeaders)
+	}
+}
+
+func TestFormatTracedBodyRedactsSensitiveValues(t *testing.T) {
+	body := `{
+		"Name": "Deploy",
+		"ApiKey": "API-MYAPIKEY",
+		"Properties": {
+			"Octopus.Action.Script.ScriptBody": "echo hello",
+			"Octopus.Action.Azure.Password": "plain-password",
+			"Octopus.Action.Azure.ClientSecret": "plain-client-secret",
+			"Octopus.Action.Aws.SessionToken": {"HasValue": true, "NewValue": "sensitive-token"},
+			"Octopus.Action.Custom": {"HasValue": true, "NewValue": "sensitive-property-value"}
+		},
+		"Variables": [
+			{"Name": "Plain", "Value": "plain-value", "Type": "String", "IsSensitive": false},
+			{"Name": "Secret", "Value": "sensitive-variable-value", "Type": "Sensitive", "IsSensitive": true}
+		]
+	}`
+
+	formattedBody := formatTracedBody([]byte(body))
+
+	for _, secret := range []string{"API-MYAPIKEY", "plain-password", "plain-client-secret", "sensitive-token", "sensitive-property-value", "sensitive-variable-value"} {
+		if strings.Contains(formattedBody, secret) {
+			t.Fatalf("Expected '%s' to be redacted, but found:\n%s", secret, formattedBody)
+		}
+	}
+	for _, value := range []string{"echo hello", "plain-value", `"HasValue": true`} {
+		if !strings.Contains(formattedBody, value) {
+			t.Fatalf("Expected '%s' to be logged, but found:\n%s", value, formattedBody)
+		}
+	}
+}
+
+func TestFormatTracedBodyOmitsNonJSONContent(t *testing.T) {
+	formattedBody := formatTracedBody([]byte("password=hunter2"))
+	if strings.Contains(formattedBody, "hunter2") {
+		t.Fatalf("Expected non-JSON content to be omitted, but found:\n%s", formattedBody)
+	}
+}
+
+func TestIsRedactedProperty(t *testing.T) {
+	testCases := []struct {
+		propertyName string
+		expected     bool
+	}{
+		{"Password", true},
+		{"apikey", true},
+		{"Octopus.Action.Azure.Password", true},
+		{"Octopus.Action.Azure.ClientSecret", true},
+		{"Octopus.Account.Token", true},
+		{"Octopus.Action.Certificate.PrivateKey", true},
+		{"Octopus.Action.Script.ScriptBody", false},
+		{"Name", false},
+		{"HasValue", false},
+	}
+
+	for _, testCase := range testCases {
+		actual := isRedactedProperty(testCase.propertyName)
+		if actual != testCase.expected {
+			t.Errorf("isRedactedProperty('%s') returned %t; expected %t.", testCase.propertyName, actual, testCase.expected)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+)
+
+// authTransport is an http.RoundTripper that adds authentication headers to each request sent to the Octopus Deploy API.
+type authTransport struct {
+	// The API key (if any) used to authenticate.
+	apiKey string
+
+	// The bearer token (if any) used to authenticate.
+	accessToken string
+
+	// The underlying transport.
+	next http.RoundTripper
+}
+
+// RoundTrip executes a single HTTP transaction.
+func (transport *authTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	// RoundTrippers must not modify the original request.
+	authenticatedRequest := new(http.Request)
+	*authenticatedRequest = *request
+	authenticatedRequest.Header = make(http.Header, len(request.Header)+1)
+	for name, values := range request.Header {
+		authenticatedRequest.Header[name] = append([]string(nil), values...)
+	}
+
+	if !isEmpty(transport.accessToken) {
+		authenticatedRequest.Header.Set("Authorization", "Bearer "+transport.accessToken)
+	} else {
+		authenticatedRequest.Header.Set("X-Octopus-ApiKey", transport.apiKey)
+	}
+
+	return transport.next.RoundTrip(authenticatedRequest)
+}
+
+// Top-level API collections that are not scoped to a space.
+var nonSpaceScopedCollections = map[string]bool{
+	"":                               true,
+	"authentication":                 true,
+	"communityactiontemplates":       true,
+	"configuration":                  true,
+	"externalsecuritygroupproviders": true,
+	"features":                       true,
+	"letsencryptconfiguration":       true,
+	"licenses":                       true,
+	"maintenanceconfiguration":       true,
+	"octopusservernodes":             true,
+	"performanceconfiguration":       true,
+	"permissions":                    true,
+	"scheduler":                      true,
+	"serverconfiguration":            true,
+	"serverstatus":                   true,
+	"smtpconfiguration":              true,
+	"spaces":                         true,
+	"upgradeconfig":                  true,
+	"userroles":                      true,
+	"users":                          true,
+}
+
+// spaceTransport is an http.RoundTripper that routes requests for space-scoped API collections to a specific space.
+//
+// For example, "/api/environments/Environments-1" becomes "/api/Spaces-2/environments/Environments-1".
+type spaceTransport struct {
+	// The Id of the target space.
+	spaceID string
+
+	// The underlying transport.
+	next http.RoundTripper
+}
+
+// RoundTrip executes a single HTTP transaction.
+func (transport *spaceTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	routedPath, ok := routeToSpace(request.URL.Path, transport.spaceID)
+	if !ok {
+		return transport.next.RoundTrip(request)
+	}
+
+	// RoundTrippers must not modify the original request.
+	routedURL := *request.URL
+	routedURL.Path = routedPath
+	routedURL.RawPath = ""
+
+	routedRequest := new(http.Request)
+	*routedRequest = *request
+	routedRequest.URL = &routedURL
+
+	return transport.next.RoundTrip(routedRequest)
+}
+
+// Compute the space-scoped equivalent of an API path.
+//
+// Returns false if the path does not need to be routed to the space (e.g. it is not space-scoped, or already targets a space).
+func routeToSpace(path string, spaceID string) (string, bool) {
+	apiIndex := strings.Index(strings.ToLower(path), "/api/")
+	if apiIndex == -1 {
+		return path, false
+	}
+	prefix := path[:apiIndex+len("/api/")]
+	relativePath := path[apiIndex+len("/api/"):]
+
+	collection := relativePath
+	if separatorIndex := strings.Index(relativePath, "/"); separatorIndex != -1 {
+		collection = relativePath[:separatorIndex]
+	}
+	if strings.HasPrefix(collection, "Spaces-") || nonSpaceScopedCollections[strings.ToLower(collection)] {
+		return path, false
+	}
+
+	return prefix + spaceID + "/" + relativePath, true
+}
@@ -1,50 +1,64 @@
 package main
 
 import (
+	"fmt"
 	"github.com/hashicorp/terraform/helper/schema"
 	"log"
 	"octopus"
 )
 
 const (
-	datasourceKeyEnvironmentSlug        = "slug"
-	datasourceKeyEnvironmentName        = "name"
+	datasourceKeyEnvironmentSlug        = datasourceKeyLookupSlug
+	datasourceKeyEnvironmentID          = datasourceKeyLookupID
+	datasourceKeyEnvironmentName        = datasourceKeyLookupName
 	datasourceKeyEnvironmentDescription = "description"
 )
 
 func datasourceEnvironment() *schema.Resource {
+	datasourceSchema := schemaDatasourceLookup("environment")
+	datasourceSchema[datasourceKeyEnvironmentDescription] = &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "The environment description.",
+	}
+
 	return &schema.Resource{
 		Read:   datasourceEnvironmentRead,
 		Exists: datasourceEnvironmentExists,
 
-		Schema: map[string]*schema.Schema{
-			datasourceKeyEnvironmentSlug: &schema.Schema{
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "The environment slug (last segment of the environment URL).",
-			},
-			datasourceKeyEnvironmentName: &schema.Schema{
-				Type:        schema.TypeString,
-				Computed:    true,
-				Description: "The environment name.",
-			},
-		},
+		Schema: datasourceSchema,
 	}
 }
 
 // Read a environment data-source.
 func datasourceEnvironmentRead(data *schema.ResourceData, provider interface{}) error {
-	slug := data.Get(datasourceKeyEnvironmentSlug).(string)
+	lookup, err := getDatasourceLookup(data, "environment")
+	if err != nil {
+		return err
+	}
 
-	log.Printf("Read environment '%s'.", slug)
+	log.Printf("Read environment %+v.", lookup)
 
-	client := provider.(*octopus.Client)
-	environment, err := client.GetEnvironment(slug)
+	client, err := provider.(*providerState).ClientForResource(data)
+	if err != nil {
+		return err
+	}
+
+	var environment *octopus.Environment
+	if !isEmpty(lookup.ID) {
+		environment, err = client.GetEnvironment(lookup.ID)
+	} else {
+		environment, err = findEnvironmentByName(client, lookup.Name)
+	}
 	if err != nil {
 		return err
 	}
 
 	if environment == nil {
+		if !isEmpty(lookup.Name) {
+			return fmt.Errorf("Cannot find an environment named '%s'.", lookup.Name)
+		}
+
 		// Environment has been deleted.
 		data.SetId("")
 
@@ -52,6 +66,8 @@ func datasourceEnvironmentRead(data *schema.ResourceData, provider interface{})
 	}
 
 	data.SetId(environment.ID)
+	data.Set(datasourceKeyEnvironmentSlug, environment.ID)
+	data.Set(datasourceKeyEnvironmentID, environment.ID)
 	data.Set(datasourceKeyEnvironmentName, environment.Name)
 	data.Set(datasourceKeyEnvironmentDescription, environment.Description)
 
@@ -64,7 +80,11 @@ func datasourceEnvironmentExists(data *schema.ResourceData, provider interface{}
 
 	log.Printf("Check if environment '%s' exists.", id)
 
-	client := provider.(*octopus.Client)
+	var client *octopus.Client
+	client, err = provider.(*providerState).ClientForResource(data)
+	if err != nil {
+		return
+	}
 
 	var environment *octopus.Environment
 	environment, err = client.GetEnvironment(id)
@@ -72,3 +92,29 @@ func datasourceEnvironmentExists(data *schema.ResourceData, provider interface{}
 
 	return
 }
+
+// Find the environment with the specified name.
+//
+// Returns nil if no environment has that name, or an error if more than one does.
+func findEnvironmentByName(client *octopus.Client, name string) (*octopus.Environment, error) {
+	environments, err := getAllEnvironments(client)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		matchingEnvironment *octopus.Environment
+		matchingIDs         []string
+	)
+	for index := range environments {
+		if environments[index].Name == name {
+			matchingEnvironment = &environments[index]
+			matchingIDs = append(matchingIDs, matchingEnvironment.ID)
+		}
+	}
+	if len(matchingIDs) > 1 {
+		return nil, ambiguousNameError("environment", name, matchingIDs)
+	}
+
+	return matchingEnvironment, nil
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+	"log"
+)
+
+const (
+	datasourceKeyEnvironmentsIDs          = datasourceKeyListIDs
+	datasourceKeyEnvironmentsEnvironments = "environments"
+)
+
+func datasourceEnvironments() *schema.Resource {
+	datasourceSchema := schemaDatasourceList("environment")
+	datasourceSchema[datasourceKeyEnvironmentsEnvironments] = &schema.Schema{
+		Type:        schema.TypeList,
+		Computed:    true,
+		Description: "The matching environments.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				datasourceKeyEnvironmentID: &schema.Schema{
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				datasourceKeyEnvironmentName: &schema.Schema{
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				datasourceKeyEnvironmentDescription: &schema.Schema{
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+			},
+		},
+	}
+
+	return &schema.Resource{
+		Read: datasourceEnvironmentsRead,
+
+		Schema: datasourceSchema,
+	}
+}
+
+// Read an environments data-source.
+func datasourceEnvironmentsRead(data *schema.ResourceData, provider interface{}) error {
+	nameFilter, err := getDatasourceNameFilter(data)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Read environments (filter = %+v).", nameFilter)
+
+	client, err := provider.(*providerState).ClientForResource(data)
+	if err != nil {
+		return err
+	}
+
+	environments, err := getAllEnvironments(client)
+	if err != nil {
+		return err
+	}
+
+	ids := make([]string, 0)
+	matchingEnvironments := make([]interface{}, 0)
+	for _, environment := range environments {
+		if !nameFilter.Matches(environment.Name) {
+			continue
+		}
+
+		ids = append(ids, environment.ID)
+		matchingEnvironments = append(matchingEnvironments, map[string]interface{}{
+			datasourceKeyEnvironmentID:          environment.ID,
+			datasourceKeyEnvironmentName:        environment.Name,
+			datasourceKeyEnvironmentDescription: environment.Description,
+		})
+	}
+
+	log.Printf("Found %d matching environments (out of %d).", len(ids), len(environments))
+
+	data.SetId(listDatasourceID(ids))
+	propertyHelper(data).SetStringList(datasourceKeyEnvironmentsIDs, ids)
+	data.Set(datasourceKeyEnvironmentsEnvironments, matchingEnvironments)
+
+	return nil
+}
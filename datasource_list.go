@@ -0,0 +1,210 @@
+package main
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"log"
+	"octopus"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	datasourceKeyListNameRegex   = "name_regex"
+	datasourceKeyListPartialName = "partial_name"
+	datasourceKeyListDisabled    = "disabled"
+	datasourceKeyListIDs         = "ids"
+)
+
+// datasourceNameFilter matches object names against the name filters of a list data-source.
+type datasourceNameFilter struct {
+	nameRegex   *regexp.Regexp
+	partialName string
+}
+
+// The schema for the name filters (and results) common to all list data-sources.
+func schemaDatasourceList(objectType string) map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		resourceKeySpaceID: schemaSpaceID(false),
+		datasourceKeyListNameRegex: &schema.Schema{
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      "",
+			ValidateFunc: validateRegex,
+			Description:  fmt.Sprintf("If specified, only %ss whose names match this regular expression will be returned.", objectType),
+		},
+		datasourceKeyListPartialName: &schema.Schema{
+			Type:        schema.TypeString,
+			Optional:    true,
+			Default:     "",
+			Description: fmt.Sprintf("If specified, only %ss whose names contain this text (case-insensitive) will be returned.", objectType),
+		},
+		datasourceKeyListIDs: &schema.Schema{
+			Type: schema.TypeList,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+			Computed:    true,
+			Description: fmt.Sprintf("The Ids of the matching %ss.", objectType),
+		},
+	}
+}
+
+// The schema for the "disabled" filter of a list data-source.
+func schemaDatasourceListDisabled(objectType string) *schema.Schema {
+	return &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		Default:      "",
+		ValidateFunc: validateOptionalBool,
+		Description:  fmt.Sprintf("If 'true', only disabled %ss will be returned; if 'false', only enabled %ss will be returned.", objectType, objectType),
+	}
+}
+
+// Get the name filter configured for a list data-source.
+func getDatasourceNameFilter(data *schema.ResourceData) (filter datasourceNameFilter, err error) {
+	nameRegex := data.Get(datasourceKeyListNameRegex).(string)
+	if !isEmpty(nameRegex) {
+		filter.nameRegex, err = regexp.Compile(nameRegex)
+		if err != nil {
+			return
+		}
+	}
+	filter.partialName = strings.ToLower(data.Get(datasourceKeyListPartialName).(string))
+
+	return
+}
+
+// Matches determines whether the specified name matches the filter.
+func (filter datasourceNameFilter) Matches(name string) bool {
+	if filter.nameRegex != nil && !filter.nameRegex.MatchString(name) {
+		return false
+	}
+
+	return strings.Contains(strings.ToLower(name), filter.partialName)
+}
+
+// Determine whether an object's disabled flag matches the "disabled" filter of a list data-source.
+func matchesDisabledFilter(data *schema.ResourceData, isDisabled bool) bool {
+	disabled := data.Get(datasourceKeyListDisabled).(string)
+	if isEmpty(disabled) {
+		return true
+	}
+
+	return strconv.FormatBool(isDisabled) == disabled
+}
+
+// Compute an Id for a list data-source from the Ids of the objects it returned.
+func listDatasourceID(ids []string) string {
+	return strconv.Itoa(schema.HashString(strings.Join(ids, ",")))
+}
+
+// Validate a property containing a regular expression.
+func validateRegex(value interface{}, key string) (warnings []string, errors []error) {
+	_, err := regexp.Compile(value.(string))
+	if err != nil {
+		errors = append(errors, fmt.Errorf("Invalid regular expression for property '%s': %s", key, err.Error()))
+	}
+
+	return
+}
+
+// Validate a string property that must be empty, "true", or "false".
+func validateOptionalBool(value interface{}, key string) (warnings []string, errors []error) {
+	switch value.(string) {
+	case "", "true", "false":
+	default:
+		errors = append(errors, fmt.Errorf("Invalid value '%s' for property '%s' (must be 'true' or 'false').", value.(string), key))
+	}
+
+	return
+}
+
+// Retrieve all environments (following the Octopus API's paging links).
+func getAllEnvironments(client *octopus.Client) (environments []octopus.Environment, err error) {
+	pageLink := ""
+	for {
+		var page *octopus.EnvironmentPage
+		page, err = client.GetEnvironmentsPage(pageLink)
+		if err != nil {
+			return
+		}
+		environments = append(environments, page.Items...)
+
+		pageLink = page.NextPageLink
+		if isEmpty(pageLink) {
+			break
+		}
+
+		log.Printf("Retrieved %d environments so far; next page is '%s'.", len(environments), pageLink)
+	}
+
+	return
+}
+
+// Retrieve all projects (following the Octopus API's paging links).
+func getAllProjects(client *octopus.Client) (projects []octopus.Project, err error) {
+	pageLink := ""
+	for {
+		var page *octopus.ProjectPage
+		page, err = client.GetProjectsPage(pageLink)
+		if err != nil {
+			return
+		}
+		projects = append(projects, page.Items...)
+
+		pageLink = page.NextPageLink
+		if isEmpty(pageLink) {
+			break
+		}
+
+		log.Printf("Retrieved %d projects so far; next page is '%s'.", len(projects), pageLink)
+	}
+
+	return
+}
+
+// Retrieve all project groups (following the Octopus API's paging links).
+func getAllProjectGroups(client *octopus.Client) (projectGroups []octopus.ProjectGroup, err error) {
+	pageLink := ""
+	for {
+		var page *octopus.ProjectGroupPage
+		page, err = client.GetProjectGroupsPage(pageLink)
+		if err != nil {
+			return
+		}
+		projectGroups = append(projectGroups, page.Items...)
+
+		pageLink = page.NextPageLink
+		if isEmpty(pageLink) {
+			break
+		}
+
+		log.Printf("Retrieved %d project groups so far; next page is '%s'.", len(projectGroups), pageLink)
+	}
+
+	return
+}
+
+// Retrieve all machines (following the Octopus API's paging links).
+func getAllMachines(client *octopus.Client) (machines []octopus.Machine, err error) {
+	pageLink := ""
+	for {
+		var page *octopus.MachinePage
+		page, err = client.GetMachinesPage(pageLink)
+		if err != nil {
+			return
+		}
+		machines = append(machines, page.Items...)
+
+		pageLink = page.NextPageLink
+		if isEmpty(pageLink) {
+			break
+		}
+
+		log.Printf("Retrieved %d machines so far; next page is '%s'.", len(machines), pageLink)
+	}
+
+	return
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+const (
+	datasourceKeyLookupSlug = "slug"
+	datasourceKeyLookupID   = "id"
+	datasourceKeyLookupName = "name"
+)
+
+// datasourceLookup represents the criteria used to find the object that a data-source refers to.
+//
+// Exactly one of ID or Name will be populated.
+type datasourceLookup struct {
+	// The object's Id.
+	ID string
+
+	// The object's name (must match exactly).
+	Name string
+}
+
+// The schema for the properties used to look up the object that a data-source refers to.
+func schemaDatasourceLookup(objectType string) map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		resourceKeySpaceID: schemaSpaceID(false),
+		datasourceKeyLookupSlug: &schema.Schema{
+			Type:          schema.TypeString,
+			Optional:      true,
+			Computed:      true,
+			ConflictsWith: []string{datasourceKeyLookupID, datasourceKeyLookupName},
+			Description:   fmt.Sprintf("The %s slug (last segment of the %s URL in Octopus UI). Deprecated; use 'id' or 'name' instead.", objectType, objectType),
+		},
+		datasourceKeyLookupID: &schema.Schema{
+			Type:          schema.TypeString,
+			Optional:      true,
+			Computed:      true,
+			ConflictsWith: []string{datasourceKeyLookupSlug, datasourceKeyLookupName},
+			Description:   fmt.Sprintf("The %s Id.", objectType),
+		},
+		datasourceKeyLookupName: &schema.Schema{
+			Type:          schema.TypeString,
+			Optional:      true,
+			Computed:      true,
+			ConflictsWith: []string{datasourceKeyLookupSlug, datasourceKeyLookupID},
+			Description:   fmt.Sprintf("The %s name.", objectType),
+		},
+	}
+}
+
+// The schema for the properties used to look up the object that a data-source refers to, for data-sources that have never supported the deprecated "slug" property.
+func schemaDatasourceIDOrNameLookup(objectType string) map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		resourceKeySpaceID: schemaSpaceID(false),
+		datasourceKeyLookupID: &schema.Schema{
+			Type:          schema.TypeString,
+			Optional:      true,
+			Computed:      true,
+			ConflictsWith: []string{datasourceKeyLookupName},
+			Description:   fmt.Sprintf("The %s Id.", objectType),
+		},
+		datasourceKeyLookupName: &schema.Schema{
+			Type:          schema.TypeString,
+			Optional:      true,
+			Computed:      true,
+			ConflictsWith: []string{datasourceKeyLookupID},
+			Description:   fmt.Sprintf("The %s name.", objectType),
+		},
+	}
+}
+
+// Get the criteria used to find the object that a data-source refers to.
+func getDatasourceLookup(data *schema.ResourceData, objectType string) (lookup datasourceLookup, err error) {
+	slug, _ := data.Get(datasourceKeyLookupSlug).(string) // Not present in schemaDatasourceIDOrNameLookup.
+	id := data.Get(datasourceKeyLookupID).(string)
+	name := data.Get(datasourceKeyLookupName).(string)
+
+	switch {
+	case !isEmpty(slug):
+		lookup.ID = slug
+	case !isEmpty(id):
+		lookup.ID = id
+	case !isEmpty(name):
+		lookup.Name = name
+	default:
+		err = fmt.Errorf("One of '%s' or '%s' must be specified for the %s data-source.", datasourceKeyLookupID, datasourceKeyLookupName, objectType)
+	}
+
+	return
+}
+
+// Create an error indicating that a name matched more than one object.
+func ambiguousNameError(objectType string, name string, ids []string) error {
+	return fmt.Errorf("Found %d %ss named '%s' (%v); use 'id' to select one of them.", len(ids), objectType, name, ids)
+}
@@ -1,61 +1,70 @@
 package main
 
 import (
+	"fmt"
 	"github.com/hashicorp/terraform/helper/schema"
 	"log"
 	"octopus"
 )
 
 const (
-	datasourceKeyMachineSlug       = "slug"
-	datasourceKeyMachineName       = "name"
+	datasourceKeyMachineSlug       = datasourceKeyLookupSlug
+	datasourceKeyMachineID         = datasourceKeyLookupID
+	datasourceKeyMachineName       = datasourceKeyLookupName
 	datasourceKeyMachineURI        = "uri"
 	datasourceKeyMachineThumbprint = "thumbprint"
 )
 
 func datasourceMachine() *schema.Resource {
+	datasourceSchema := schemaDatasourceLookup("machine")
+	datasourceSchema[datasourceKeyMachineURI] = &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "The machine URI.",
+	}
+	datasourceSchema[datasourceKeyMachineThumbprint] = &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "The machine thumbprint.",
+	}
+
 	return &schema.Resource{
 		Read:   datasourceMachineRead,
 		Exists: datasourceMachineExists,
 
-		Schema: map[string]*schema.Schema{
-			datasourceKeyMachineSlug: &schema.Schema{
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "The machine slug (last segment of the machine URL in Octopus UI).",
-			},
-			datasourceKeyMachineName: &schema.Schema{
-				Type:        schema.TypeString,
-				Computed:    true,
-				Description: "The machine name.",
-			},
-			datasourceKeyMachineURI: &schema.Schema{
-				Type:        schema.TypeString,
-				Computed:    true,
-				Description: "The machine URI.",
-			},
-			datasourceKeyMachineThumbprint: &schema.Schema{
-				Type:        schema.TypeString,
-				Computed:    true,
-				Description: "The machine thumbprint.",
-			},
-		},
+		Schema: datasourceSchema,
 	}
 }
 
 // Read a machine data-source.
 func datasourceMachineRead(data *schema.ResourceData, provider interface{}) error {
-	slug := data.Get(datasourceKeyMachineSlug).(string)
+	lookup, err := getDatasourceLookup(data, "machine")
+	if err != nil {
+		return err
+	}
 
-	log.Printf("Read machine '%s'.", slug)
+	log.Printf("Read machine %+v.", lookup)
 
-	client := provider.(*octopus.Client)
-	machine, err := client.GetMachine(slug)
+	client, err := provider.(*providerState).ClientForResource(data)
+	if err != nil {
+		return err
+	}
+
+	var machine *octopus.Machine
+	if !isEmpty(lookup.ID) {
+		machine, err = client.GetMachine(lookup.ID)
+	} else {
+		machine, err = findMachineByName(client, lookup.Name)
+	}
 	if err != nil {
 		return err
 	}
 
 	if machine == nil {
+		if !isEmpty(lookup.Name) {
+			return fmt.Errorf("Cannot find a machine named '%s'.", lookup.Name)
+		}
+
 		// Machine has been deleted.
 		data.SetId("")
 
@@ -63,6 +72,8 @@ func datasourceMachineRead(data *schema.ResourceData, provider interface{}) erro
 	}
 
 	data.SetId(machine.ID)
+	data.Set(datasourceKeyMachineSlug, machine.ID)
+	data.Set(datasourceKeyMachineID, machine.ID)
 	data.Set(datasourceKeyMachineName, machine.Name)
 	data.Set(datasourceKeyMachineURI, machine.URI)
 	data.Set(datasourceKeyMachineThumbprint, machine.Thumbprint)
@@ -72,15 +83,45 @@ func datasourceMachineRead(data *schema.ResourceData, provider interface{}) erro
 
 // Determine whether a machine datasource exists.
 func datasourceMachineExists(data *schema.ResourceData, provider interface{}) (exists bool, err error) {
-	slug := data.Get(datasourceKeyMachineSlug).(string)
+	id := data.Id()
 
-	log.Printf("Check if machine '%s' exists.", slug)
+	log.Printf("Check if machine '%s' exists.", id)
 
-	client := provider.(*octopus.Client)
+	var client *octopus.Client
+	client, err = provider.(*providerState).ClientForResource(data)
+	if err != nil {
+		return
+	}
 
 	var machine *octopus.Machine
-	machine, err = client.GetMachine(slug)
+	machine, err = client.GetMachine(id)
 	exists = machine != nil
 
 	return
 }
+
+// Find the machine with the specified name.
+//
+// Returns nil if no machine has that name, or an error if more than one does.
+func findMachineByName(client *octopus.Client, name string) (*octopus.Machine, error) {
+	machines, err := getAllMachines(client)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		matchingMachine *octopus.Machine
+		matchingIDs     []string
+	)
+	for index := range machines {
+		if machines[index].Name == name {
+			matchingMachine = &machines[index]
+			matchingIDs = append(matchingIDs, matchingMachine.ID)
+		}
+	}
+	if len(matchingIDs) > 1 {
+		return nil, ambiguousNameError("machine", name, matchingIDs)
+	}
+
+	return matchingMachine, nil
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+	"log"
+)
+
+const (
+	datasourceKeyMachinesIDs          = datasourceKeyListIDs
+	datasourceKeyMachinesEnvironment  = "environment"
+	datasourceKeyMachinesRole         = "role"
+	datasourceKeyMachinesHealthStatus = "health_status"
+	datasourceKeyMachinesDisabled     = datasourceKeyListDisabled
+	datasourceKeyMachinesMachines     = "machines"
+	datasourceKeyMachinesEnvironments = "environments"
+	datasourceKeyMachinesRoles        = "roles"
+	datasourceKeyMachinesIsDisabled   = "is_disabled"
+)
+
+func datasourceMachines() *schema.Resource {
+	datasourceSchema := schemaDatasourceList("machine")
+	datasourceSchema[datasourceKeyMachinesEnvironment] = &schema.Schema{
+		Type:        schema.TypeString,
+		Optional:    true,
+		Default:     "",
+		Description: "If specified, only machines in the environment with this Id will be returned.",
+	}
+	datasourceSchema[datasourceKeyMachinesRole] = &schema.Schema{
+		Type:        schema.TypeString,
+		Optional:    true,
+		Default:     "",
+		Description: "If specified, only machines with this role will be returned.",
+	}
+	datasourceSchema[datasourceKeyMachinesHealthStatus] = &schema.Schema{
+		Type:        schema.TypeString,
+		Optional:    true,
+		Default:     "",
+		Description: "If specified, only machines with this health status (e.g. Healthy, HasWarnings, Unhealthy, Unavailable) will be returned.",
+	}
+	datasourceSchema[datasourceKeyMachinesDisabled] = schemaDatasourceListDisabled("machine")
+	datasourceSchema[datasourceKeyMachinesMachines] = &schema.Schema{
+		Type:        schema.TypeList,
+		Computed:    true,
+		Description: "The matching machines.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				datasourceKeyMachineID: &schema.Schema{
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				datasourceKeyMachineName: &schema.Schema{
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				datasourceKeyMachineURI: &schema.Schema{
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				datasourceKeyMachineThumbprint: &schema.Schema{
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				datasourceKeyMachinesEnvironments: &schema.Schema{
+					Type: schema.TypeList,
+					Elem: &schema.Schema{
+						Type: schema.TypeString,
+					},
+					Computed: true,
+				},
+				datasourceKeyMachinesRoles: &schema.Schema{
+					Type: schema.TypeList,
+					Elem: &schema.Schema{
+						Type: schema.TypeString,
+					},
+					Computed: true,
+				},
+				datasourceKeyMachinesHealthStatus: &schema.Schema{
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				datasourceKeyMachinesIsDisabled: &schema.Schema{
+					Type:     schema.TypeBool,
+					Computed: true,
+				},
+			},
+		},
+	}
+
+	return &schema.Resource{
+		Read: datasourceMachinesRead,
+
+		Schema: datasourceSchema,
+	}
+}
+
+// Read a machines data-source.
+func datasourceMachinesRead(data *schema.ResourceData, provider interface{}) error {
+	nameFilter, err := getDatasourceNameFilter(data)
+	if err != nil {
+		return err
+	}
+	environmentID := data.Get(datasourceKeyMachinesEnvironment).(string)
+	role := data.Get(datasourceKeyMachinesRole).(string)
+	healthStatus := data.Get(datasourceKeyMachinesHealthStatus).(string)
+
+	log.Printf("Read machines (filter = %+v, environment = '%s', role = '%s', health status = '%s').", nameFilter, environmentID, role, healthStatus)
+
+	client, err := provider.(*providerState).ClientForResource(data)
+	if err != nil {
+		return err
+	}
+
+	machines, err := getAllMachines(client)
+	if err != nil {
+		return err
+	}
+
+	ids := make([]string, 0)
+	matchingMachines := make([]interface{}, 0)
+	for _, machine := range machines {
+		if !nameFilter.Matches(machine.Name) {
+			continue
+		}
+		if !isEmpty(environmentID) && !containsString(machine.EnvironmentIDs, environmentID) {
+			continue
+		}
+		if !isEmpty(role) && !containsString(machine.Roles, role) {
+			continue
+		}
+		if !isEmpty(healthStatus) && machine.HealthStatus != healthStatus {
+			continue
+		}
+		if !matchesDisabledFilter(data, machine.IsDisabled) {
+			continue
+		}
+
+		ids = append(ids, machine.ID)
+		matchingMachines = append(matchingMachines, map[string]interface{}{
+			datasourceKeyMachineID:            machine.ID,
+			datasourceKeyMachineName:          machine.Name,
+			datasourceKeyMachineURI:           machine.URI,
+			datasourceKeyMachineThumbprint:    machine.Thumbprint,
+			datasourceKeyMachinesEnvironments: machine.EnvironmentIDs,
+			datasourceKeyMachinesRoles:        machine.Roles,
+			datasourceKeyMachinesHealthStatus: machine.HealthStatus,
+			datasourceKeyMachinesIsDisabled:   machine.IsDisabled,
+		})
+	}
+
+	log.Printf("Found %d matching machines (out of %d).", len(ids), len(machines))
+
+	data.SetId(listDatasourceID(ids))
+	propertyHelper(data).SetStringList(datasourceKeyMachinesIDs, ids)
+	data.Set(datasourceKeyMachinesMachines, matchingMachines)
+
+	return nil
+}
@@ -1,14 +1,16 @@
 package main
 
 import (
+	"fmt"
 	"github.com/hashicorp/terraform/helper/schema"
 	"log"
 	"octopus"
 )
 
 const (
-	datasourceKeyProjectSlug = "slug"
-	datasourceKeyProjectName = "name"
+	datasourceKeyProjectSlug = datasourceKeyLookupSlug
+	datasourceKeyProjectID   = datasourceKeyLookupID
+	datasourceKeyProjectName = datasourceKeyLookupName
 )
 
 func datasourceProject() *schema.Resource {
@@ -16,34 +18,39 @@ func datasourceProject() *schema.Resource {
 		Read:   datasourceProjectRead,
 		Exists: datasourceProjectExists,
 
-		Schema: map[string]*schema.Schema{
-			datasourceKeyProjectSlug: &schema.Schema{
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "The project slug (last segment of the project URL in Octopus UI).",
-			},
-			datasourceKeyProjectName: &schema.Schema{
-				Type:        schema.TypeString,
-				Computed:    true,
-				Description: "The project name.",
-			},
-		},
+		Schema: schemaDatasourceLookup("project"),
 	}
 }
 
 // Read a project data-source.
 func datasourceProjectRead(data *schema.ResourceData, provider interface{}) error {
-	slug := data.Get(datasourceKeyProjectSlug).(string)
+	lookup, err := getDatasourceLookup(data, "project")
+	if err != nil {
+		return err
+	}
 
-	log.Printf("Read project '%s'.", slug)
+	log.Printf("Read project %+v.", lookup)
+
+	client, err := provider.(*providerState).ClientForResource(data)
+	if err != nil {
+		return err
+	}
 
-	client := provider.(*octopus.Client)
-	project, err := client.GetProject(slug)
+	var project *octopus.Project
+	if !isEmpty(lookup.ID) {
+		project, err = client.GetProject(lookup.ID)
+	} else {
+		project, err = findProjectByName(client, lookup.Name)
+	}
 	if err != nil {
 		return err
 	}
 
 	if project == nil {
+		if !isEmpty(lookup.Name) {
+			return fmt.Errorf("Cannot find a project named '%s'.", lookup.Name)
+		}
+
 		// Project has been deleted.
 		data.SetId("")
 
@@ -51,6 +58,8 @@ func datasourceProjectRead(data *schema.ResourceData, provider interface{}) erro
 	}
 
 	data.SetId(project.ID)
+	data.Set(datasourceKeyProjectSlug, project.ID)
+	data.Set(datasourceKeyProjectID, project.ID)
 	data.Set(datasourceKeyProjectName, project.Name)
 
 	return nil
@@ -62,7 +71,11 @@ func datasourceProjectExists(data *schema.ResourceData, provider interface{}) (e
 
 	log.Printf("Check if project '%s' exists.", id)
 
-	client := provider.(*octopus.Client)
+	var client *octopus.Client
+	client, err = provider.(*providerState).ClientForResource(data)
+	if err != nil {
+		return
+	}
 
 	var project *octopus.Project
 	project, err = client.GetProject(id)
@@ -70,3 +83,29 @@ func datasourceProjectExists(data *schema.ResourceData, provider interface{}) (e
 
 	return
 }
+
+// Find the project with the specified name.
+//
+// Returns nil if no project has that name, or an error if more than one does.
+func findProjectByName(client *octopus.Client, name string) (*octopus.Project, error) {
+	projects, err := getAllProjects(client)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		matchingProject *octopus.Project
+		matchingIDs     []string
+	)
+	for index := range projects {
+		if projects[index].Name == name {
+			matchingProject = &projects[index]
+			matchingIDs = append(matchingIDs, matchingProject.ID)
+		}
+	}
+	if len(matchingIDs) > 1 {
+		return nil, ambiguousNameError("project", name, matchingIDs)
+	}
+
+	return matchingProject, nil
+}
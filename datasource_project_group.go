@@ -0,0 +1,135 @@
+package main
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"log"
+	"octopus"
+)
+
+const (
+	datasourceKeyProjectGroupID                = datasourceKeyLookupID
+	datasourceKeyProjectGroupName              = datasourceKeyLookupName
+	datasourceKeyProjectGroupDescription       = "description"
+	datasourceKeyProjectGroupRetentionPolicyID = "retention_policy"
+	datasourceKeyProjectGroupEnvironments      = "environments"
+)
+
+func datasourceProjectGroup() *schema.Resource {
+	datasourceSchema := schemaDatasourceIDOrNameLookup("project group")
+	datasourceSchema[datasourceKeyProjectGroupDescription] = &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "The project group description.",
+	}
+	datasourceSchema[datasourceKeyProjectGroupRetentionPolicyID] = &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "The Id of the project group's retention policy.",
+	}
+	datasourceSchema[datasourceKeyProjectGroupEnvironments] = &schema.Schema{
+		Type: schema.TypeList,
+		Elem: &schema.Schema{
+			Type: schema.TypeString,
+		},
+		Computed:    true,
+		Description: "The Ids of environments associated with the project group.",
+	}
+
+	return &schema.Resource{
+		Read:   datasourceProjectGroupRead,
+		Exists: datasourceProjectGroupExists,
+
+		Schema: datasourceSchema,
+	}
+}
+
+// Read a project group data-source.
+func datasourceProjectGroupRead(data *schema.ResourceData, provider interface{}) error {
+	lookup, err := getDatasourceLookup(data, "project group")
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Read project group %+v.", lookup)
+
+	client, err := provider.(*providerState).ClientForResource(data)
+	if err != nil {
+		return err
+	}
+
+	var projectGroup *octopus.ProjectGroup
+	if !isEmpty(lookup.ID) {
+		projectGroup, err = client.GetProjectGroup(lookup.ID)
+	} else {
+		projectGroup, err = findProjectGroupByName(client, lookup.Name)
+	}
+	if err != nil {
+		return err
+	}
+
+	if projectGroup == nil {
+		if !isEmpty(lookup.Name) {
+			return fmt.Errorf("Cannot find a project group named '%s'.", lookup.Name)
+		}
+
+		// Project group has been deleted.
+		data.SetId("")
+
+		return nil
+	}
+
+	data.SetId(projectGroup.ID)
+	data.Set(datasourceKeyProjectGroupID, projectGroup.ID)
+	data.Set(datasourceKeyProjectGroupName, projectGroup.Name)
+	data.Set(datasourceKeyProjectGroupDescription, projectGroup.Description)
+	data.Set(datasourceKeyProjectGroupRetentionPolicyID, projectGroup.RetentionPolicyID)
+	propertyHelper(data).SetStringList(datasourceKeyProjectGroupEnvironments, projectGroup.EnvironmentIDs)
+
+	return nil
+}
+
+// Determine whether a project group datasource exists.
+func datasourceProjectGroupExists(data *schema.ResourceData, provider interface{}) (exists bool, err error) {
+	id := data.Id()
+
+	log.Printf("Check if project group '%s' exists.", id)
+
+	var client *octopus.Client
+	client, err = provider.(*providerState).ClientForResource(data)
+	if err != nil {
+		return
+	}
+
+	var projectGroup *octopus.ProjectGroup
+	projectGroup, err = client.GetProjectGroup(id)
+	exists = projectGroup != nil
+
+	return
+}
+
+// Find the project group with the specified name.
+//
+// Returns nil if no project group has that name, or an error if more than one does.
+func findProjectGroupByName(client *octopus.Client, name string) (*octopus.ProjectGroup, error) {
+	projectGroups, err := getAllProjectGroups(client)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		matchingProjectGroup *octopus.ProjectGroup
+		matchingIDs          []string
+	)
+	for index := range projectGroups {
+		if projectGroups[index].Name == name {
+			matchingProjectGroup = &projectGroups[index]
+			matchingIDs = append(matchingIDs, matchingProjectGroup.ID)
+		}
+	}
+	if len(matchingIDs) > 1 {
+		return nil, ambiguousNameError("project group", name, matchingIDs)
+	}
+
+	return matchingProjectGroup, nil
+}
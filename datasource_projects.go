@@ -0,0 +1,113 @@
+package main
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+	"log"
+)
+
+const (
+	datasourceKeyProjectsIDs          = datasourceKeyListIDs
+	datasourceKeyProjectsProjectGroup = "project_group"
+	datasourceKeyProjectsDisabled     = datasourceKeyListDisabled
+	datasourceKeyProjectsProjects     = "projects"
+	datasourceKeyProjectsDescription  = "description"
+	datasourceKeyProjectsIsDisabled   = "is_disabled"
+)
+
+func datasourceProjects() *schema.Resource {
+	datasourceSchema := schemaDatasourceList("project")
+	datasourceSchema[datasourceKeyProjectsProjectGroup] = &schema.Schema{
+		Type:        schema.TypeString,
+		Optional:    true,
+		Default:     "",
+		Description: "If specified, only projects in the project group with this Id will be returned.",
+	}
+	datasourceSchema[datasourceKeyProjectsDisabled] = schemaDatasourceListDisabled("project")
+	datasourceSchema[datasourceKeyProjectsProjects] = &schema.Schema{
+		Type:        schema.TypeList,
+		Computed:    true,
+		Description: "The matching projects.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				datasourceKeyProjectID: &schema.Schema{
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				datasourceKeyProjectName: &schema.Schema{
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				datasourceKeyProjectsDescription: &schema.Schema{
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				datasourceKeyProjectsProjectGroup: &schema.Schema{
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				datasourceKeyProjectsIsDisabled: &schema.Schema{
+					Type:     schema.TypeBool,
+					Computed: true,
+				},
+			},
+		},
+	}
+
+	return &schema.Resource{
+		Read: datasourceProjectsRead,
+
+		Schema: datasourceSchema,
+	}
+}
+
+// Read a projects data-source.
+func datasourceProjectsRead(data *schema.ResourceData, provider interface{}) error {
+	nameFilter, err := getDatasourceNameFilter(data)
+	if err != nil {
+		return err
+	}
+	projectGroupID := data.Get(datasourceKeyProjectsProjectGroup).(string)
+
+	log.Printf("Read projects (filter = %+v, project group = '%s').", nameFilter, projectGroupID)
+
+	client, err := provider.(*providerState).ClientForResource(data)
+	if err != nil {
+		return err
+	}
+
+	projects, err := getAllProjects(client)
+	if err != nil {
+		return err
+	}
+
+	ids := make([]string, 0)
+	matchingProjects := make([]interface{}, 0)
+	for _, project := range projects {
+		if !nameFilter.Matches(project.Name) {
+			continue
+		}
+		if !isEmpty(projectGroupID) && project.ProjectGroupID != projectGroupID {
+			continue
+		}
+		if !matchesDisabledFilter(data, project.IsDisabled) {
+			continue
+		}
+
+		ids = append(ids, project.ID)
+		matchingProjects = append(matchingProjects, map[string]interface{}{
+			datasourceKeyProjectID:            project.ID,
+			datasourceKeyProjectName:          project.Name,
+			datasourceKeyProjectsDescription:  project.Description,
+			datasourceKeyProjectsProjectGroup: project.ProjectGroupID,
+			datasourceKeyProjectsIsDisabled:   project.IsDisabled,
+		})
+	}
+
+	log.Printf("Found %d matching projects (out of %d).", len(ids), len(projects))
+
+	data.SetId(listDatasourceID(ids))
+	propertyHelper(data).SetStringList(datasourceKeyProjectsIDs, ids)
+	data.Set(datasourceKeyProjectsProjects, matchingProjects)
+
+	return nil
+}
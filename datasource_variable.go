@@ -8,13 +8,19 @@ import (
 )
 
 const (
-	datasourceKeyVariableProjectID = "project"
-	datasourceKeyVariableName      = "name"
-	datasourceKeyVariableValue     = "value"
-	datasourceKeyVariableVariables = "variables"
-	datasourceKeyVariableRoles     = "roles"
-	datasourceKeyVariableMachines  = "machines"
-	datasourceKeyVariableActions   = "actions"
+	datasourceKeyVariableProjectID            = "project"
+	datasourceKeyVariableLibraryVariableSetID = "library_variable_set"
+	datasourceKeyVariableName                 = "name"
+	datasourceKeyVariableValue                = "value"
+	datasourceKeyVariableEnvironments         = "environments"
+	datasourceKeyVariableRoles                = "roles"
+	datasourceKeyVariableMachines             = "machines"
+	datasourceKeyVariableActions              = "actions"
+	datasourceKeyVariableChannels             = "channels"
+	datasourceKeyVariableTenantTags           = "tenant_tags"
+	datasourceKeyVariableProcesses            = "processes"
+	datasourceKeyVariableType                 = "type"
+	datasourceKeyVariableIsSensitive          = "is_sensitive"
 )
 
 func datasourceVariable() *schema.Resource {
@@ -23,46 +29,103 @@ func datasourceVariable() *schema.Resource {
 		Exists: datasourceVariableExists,
 
 		Schema: map[string]*schema.Schema{
+			resourceKeySpaceID: schemaSpaceID(false),
 			datasourceKeyVariableProjectID: &schema.Schema{
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "The variable name.",
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{datasourceKeyVariableLibraryVariableSetID},
+				Description:   "The Id of the project that owns the variable.",
+			},
+			datasourceKeyVariableLibraryVariableSetID: &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{datasourceKeyVariableProjectID},
+				Description:   "The Id of the library variable set that owns the variable.",
 			},
 			datasourceKeyVariableName: &schema.Schema{
 				Type:     schema.TypeString,
 				Required: true,
 			},
 			datasourceKeyVariableValue: &schema.Schema{
-				Type:     schema.TypeString,
-				Computed: true,
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The variable value (empty if the variable is sensitive, since Octopus never returns the values of sensitive variables).",
+			},
+			datasourceKeyVariableIsSensitive: &schema.Schema{
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Is the variable sensitive?",
 			},
-			datasourceKeyVariableVariables: &schema.Schema{
+			datasourceKeyVariableType: &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validateVariableType,
+				Description:  "The variable type (if specified, the variable must have this type).",
+			},
+			datasourceKeyVariableEnvironments: &schema.Schema{
 				Type: schema.TypeList,
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
 				},
-				Optional: true,
+				Optional:    true,
+				Computed:    true,
+				Description: "The environment Ids that the variable is scoped to (if specified, the variable's scope must contain exactly these environment Ids; otherwise, variables with any scope match). Always set to the matching variable's scope.",
 			},
 			datasourceKeyVariableRoles: &schema.Schema{
 				Type: schema.TypeList,
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
 				},
-				Optional: true,
+				Optional:    true,
+				Computed:    true,
+				Description: "The machine roles that the variable is scoped to (if specified, the variable's scope must contain exactly these machine roles; otherwise, variables with any scope match). Always set to the matching variable's scope.",
 			},
 			datasourceKeyVariableMachines: &schema.Schema{
 				Type: schema.TypeList,
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
 				},
-				Optional: true,
+				Optional:    true,
+				Computed:    true,
+				Description: "The machine Ids that the variable is scoped to (if specified, the variable's scope must contain exactly these machine Ids; otherwise, variables with any scope match). Always set to the matching variable's scope.",
 			},
 			datasourceKeyVariableActions: &schema.Schema{
 				Type: schema.TypeList,
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
 				},
-				Optional: true,
+				Optional:    true,
+				Computed:    true,
+				Description: "The deployment action Ids that the variable is scoped to (if specified, the variable's scope must contain exactly these deployment action Ids; otherwise, variables with any scope match). Always set to the matching variable's scope.",
+			},
+			datasourceKeyVariableChannels: &schema.Schema{
+				Type: schema.TypeList,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Optional:    true,
+				Computed:    true,
+				Description: "The channel Ids that the variable is scoped to (if specified, the variable's scope must contain exactly these channel Ids; otherwise, variables with any scope match). Always set to the matching variable's scope.",
+			},
+			datasourceKeyVariableTenantTags: &schema.Schema{
+				Type: schema.TypeList,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Optional:    true,
+				Computed:    true,
+				Description: "The tenant tags that the variable is scoped to (if specified, the variable's scope must contain exactly these tenant tags; otherwise, variables with any scope match). Always set to the matching variable's scope.",
+			},
+			datasourceKeyVariableProcesses: &schema.Schema{
+				Type: schema.TypeList,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Optional:    true,
+				Computed:    true,
+				Description: "The process owner Ids that the variable is scoped to (if specified, the variable's scope must contain exactly these process owner Ids; otherwise, variables with any scope match). Always set to the matching variable's scope.",
 			},
 		},
 	}
@@ -70,39 +133,61 @@ func datasourceVariable() *schema.Resource {
 
 // Read a variable data-source.
 func datasourceVariableRead(data *schema.ResourceData, provider interface{}) error {
-	projectID := data.Get(resourceKeyVariableProjectID).(string)
 	name := data.Get(resourceKeyVariableName).(string)
-
-	propertyHelper := propertyHelper(data)
-	targetScope := octopus.VariableScopes{
-		Environments: propertyHelper.GetStringList(resourceKeyVariableEnvironments),
-		Roles:        propertyHelper.GetStringList(resourceKeyVariableRoles),
-		Machines:     propertyHelper.GetStringList(resourceKeyVariableMachines),
-		Actions:      propertyHelper.GetStringList(resourceKeyVariableActions),
+	owner, err := getVariableOwner(data)
+	if err != nil {
+		return err
 	}
 
-	log.Printf("Read variable '%s' in project '%s', targeting scope %+v", name, projectID, targetScope)
+	targetScope := getVariableScopes(data)
 
-	client := provider.(*octopus.Client)
+	log.Printf("Read variable '%s' in %s, targeting scope %+v", name, owner, targetScope)
+
+	client, err := provider.(*providerState).ClientForResource(data)
+	if err != nil {
+		return err
+	}
 
-	variableSet, err := client.GetProjectVariableSet(projectID)
+	variableSet, err := owner.GetVariableSet(client)
 	if err != nil {
-		return fmt.Errorf("Error retrieving variable set for project '%s': %s", projectID, err.Error())
+		return fmt.Errorf("Error retrieving variable set for %s: %s", owner, err.Error())
 	}
 	if variableSet == nil {
-		return fmt.Errorf("Cannot find variable set for project '%s'", projectID)
+		return fmt.Errorf("Cannot find variable set for %s", owner)
 	}
 
-	matchingVariables := variableSet.GetVariablesByNameAndScopes(name, targetScope)
+	matchingVariables := findVariablesByNameAndSpecifiedScopes(variableSet, name, targetScope)
+	if len(matchingVariables) > 1 {
+		// Prefer the variable whose scopes are exactly the specified scopes (e.g. the unscoped variable, if no scopes are specified).
+		exactlyMatchingVariables := findVariablesByNameAndScopes(&octopus.VariableSet{Variables: matchingVariables}, name, targetScope)
+		if len(exactlyMatchingVariables) == 1 {
+			matchingVariables = exactlyMatchingVariables
+		}
+	}
 	if len(matchingVariables) == 0 {
-		return fmt.Errorf("Cannot find variable '%s' in project '%s' with scope %+v", name, projectID, targetScope)
+		return fmt.Errorf("Cannot find variable '%s' in %s with scope %+v", name, owner, targetScope)
 	} else if len(matchingVariables) != 1 {
-		return fmt.Errorf("Multiple variables exactly match name '%s' in project '%s' with scope %+v", name, projectID, targetScope)
+		return fmt.Errorf("Multiple variables named '%s' in %s match scope %+v (specify more scopes to select one of them)", name, owner, targetScope)
 	}
 
 	variable := matchingVariables[0]
+
+	expectedType := data.Get(datasourceKeyVariableType).(string)
+	if !isEmpty(expectedType) && variable.Type != expectedType {
+		return fmt.Errorf("Variable '%s' in %s has type '%s' (expected '%s')", name, owner, variable.Type, expectedType)
+	}
+
 	data.SetId(variable.ID)
 	data.Set(datasourceKeyVariableName, variable.Name)
+	data.Set(datasourceKeyVariableType, variable.Type)
+	data.Set(datasourceKeyVariableIsSensitive, variable.IsSensitive)
+	if variable.IsSensitive {
+		data.Set(datasourceKeyVariableValue, "")
+	} else {
+		data.Set(datasourceKeyVariableValue, variable.Value)
+	}
+
+	setVariableScopes(data, variable.Scope)
 
 	return nil
 }
@@ -110,21 +195,30 @@ func datasourceVariableRead(data *schema.ResourceData, provider interface{}) err
 // Determine whether a variable data-source exists.
 func datasourceVariableExists(data *schema.ResourceData, provider interface{}) (exists bool, err error) {
 	id := data.Id()
-	projectID := data.Get(resourceKeyVariableProjectID).(string)
 	name := data.Get(resourceKeyVariableName).(string)
 
-	log.Printf("Check if variable '%s' (name = '%s') exists in project '%s'", id, name, projectID)
+	var owner variableOwner
+	owner, err = getVariableOwner(data)
+	if err != nil {
+		return
+	}
 
-	client := provider.(*octopus.Client)
+	log.Printf("Check if variable '%s' (name = '%s') exists in %s", id, name, owner)
+
+	var client *octopus.Client
+	client, err = provider.(*providerState).ClientForResource(data)
+	if err != nil {
+		return
+	}
 
-	variableSet, err := client.GetProjectVariableSet(projectID)
+	variableSet, err := owner.GetVariableSet(client)
 	if err != nil {
-		err = fmt.Errorf("Error retrieving variable set for project '%s': %s", projectID, err.Error())
+		err = fmt.Errorf("Error retrieving variable set for %s: %s", owner, err.Error())
 
 		return
 	}
 	if variableSet == nil {
-		err = fmt.Errorf("Cannot find variable set for project '%s'", projectID)
+		err = fmt.Errorf("Cannot find variable set for %s", owner)
 
 		return
 	}
@@ -0,0 +1,608 @@
+package main
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+	"octopus"
+	"strconv"
+	"strings"
+)
+
+const (
+	resourceKeyDeploymentStepID                  = "id"
+	resourceKeyDeploymentStepName                = "name"
+	resourceKeyDeploymentStepCondition           = "condition"
+	resourceKeyDeploymentStepConditionExpression = "condition_expression"
+	resourceKeyDeploymentStepStartTrigger        = "start_trigger"
+	resourceKeyDeploymentStepPackageRequirement  = "package_requirement"
+	resourceKeyDeploymentStepTargetRoles         = "target_roles"
+	resourceKeyDeploymentStepWindowSize          = "window_size"
+	resourceKeyDeploymentStepAction              = "action"
+
+	resourceKeyDeploymentActionID                   = "id"
+	resourceKeyDeploymentActionName                 = "name"
+	resourceKeyDeploymentActionType                 = "action_type"
+	resourceKeyDeploymentActionProperties           = "properties"
+	resourceKeyDeploymentActionSensitiveProperties  = "sensitive_properties"
+	resourceKeyDeploymentActionPackage              = "package"
+	resourceKeyDeploymentActionEnvironments         = "environments"
+	resourceKeyDeploymentActionExcludedEnvironments = "excluded_environments"
+	resourceKeyDeploymentActionChannels             = "channels"
+	resourceKeyDeploymentActionTenantTags           = "tenant_tags"
+	resourceKeyDeploymentActionContainer            = "container"
+	resourceKeyDeploymentActionWorkerPool           = "worker_pool"
+	resourceKeyDeploymentActionIsDisabled           = "is_disabled"
+	resourceKeyDeploymentActionIsRequired           = "is_required"
+
+	resourceKeyDeploymentActionPackageID                  = "id"
+	resourceKeyDeploymentActionPackageName                = "name"
+	resourceKeyDeploymentActionPackagePackageID           = "package_id"
+	resourceKeyDeploymentActionPackageFeed                = "feed"
+	resourceKeyDeploymentActionPackageAcquisitionLocation = "acquisition_location"
+	resourceKeyDeploymentActionPackageProperties          = "properties"
+
+	resourceKeyDeploymentActionContainerImage = "image"
+	resourceKeyDeploymentActionContainerFeed  = "feed"
+)
+
+const (
+	stepConditionSuccess  = "Success"
+	stepConditionFailure  = "Failure"
+	stepConditionAlways   = "Always"
+	stepConditionVariable = "Variable"
+
+	stepStartTriggerStartAfterPrevious = "StartAfterPrevious"
+	stepStartTriggerStartWithPrevious  = "StartWithPrevious"
+
+	stepPackageRequirementLetOctopusDecide         = "LetOctopusDecide"
+	stepPackageRequirementBeforePackageAcquisition = "BeforePackageAcquisition"
+	stepPackageRequirementAfterPackageAcquisition  = "AfterPackageAcquisition"
+
+	// The step properties that hold the step's target roles, window size, and condition expression.
+	stepPropertyTargetRoles         = "Octopus.Action.TargetRoles"
+	stepPropertyWindowSize          = "Octopus.Action.MaxParallelism"
+	stepPropertyConditionExpression = "Octopus.Step.ConditionVariableExpression"
+
+	// The Id of the built-in package feed.
+	builtInFeedID = "feeds-builtin"
+)
+
+// The schema for a deployment process's steps.
+func schemaDeploymentSteps() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		Description: "The steps in the deployment process, in the order that they are run.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				resourceKeyDeploymentStepID: &schema.Schema{
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "The step Id.",
+				},
+				resourceKeyDeploymentStepName: &schema.Schema{
+					Type:        schema.TypeString,
+					Required:    true,
+					Description: "The step name.",
+				},
+				resourceKeyDeploymentStepCondition: &schema.Schema{
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      stepConditionSuccess,
+					ValidateFunc: validation.StringInSlice([]string{stepConditionSuccess, stepConditionFailure, stepConditionAlways, stepConditionVariable}, false),
+					Description:  "When the step runs (Success, Failure, Always, or Variable).",
+				},
+				resourceKeyDeploymentStepConditionExpression: &schema.Schema{
+					Type:        schema.TypeString,
+					Optional:    true,
+					Default:     "",
+					Description: "The variable expression that determines whether the step runs (required when condition is Variable).",
+				},
+				resourceKeyDeploymentStepStartTrigger: &schema.Schema{
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      stepStartTriggerStartAfterPrevious,
+					ValidateFunc: validation.StringInSlice([]string{stepStartTriggerStartAfterPrevious, stepStartTriggerStartWithPrevious}, false),
+					Description:  "Does the step start after the previous step completes, or in parallel with it (StartAfterPrevious or StartWithPrevious)?",
+				},
+				resourceKeyDeploymentStepPackageRequirement: &schema.Schema{
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      stepPackageRequirementLetOctopusDecide,
+					ValidateFunc: validation.StringInSlice([]string{stepPackageRequirementLetOctopusDecide, stepPackageRequirementBeforePackageAcquisition, stepPackageRequirementAfterPackageAcquisition}, false),
+					Description:  "When the step runs relative to package acquisition (LetOctopusDecide, BeforePackageAcquisition, or AfterPackageAcquisition).",
+				},
+				resourceKeyDeploymentStepTargetRoles: &schema.Schema{
+					Type: schema.TypeSet,
+					Elem: &schema.Schema{
+						Type: schema.TypeString,
+					},
+					Set:         schema.HashString,
+					Optional:    true,
+					Description: "The roles of the deployment targets that the step runs on (if not specified, the step runs on the Octopus server or a worker).",
+				},
+				resourceKeyDeploymentStepWindowSize: &schema.Schema{
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      "",
+					ValidateFunc: validateWindowSize,
+					Description:  "The maximum number of deployment targets that the step runs on at the same time (if not specified, the step runs on all of them at once).",
+				},
+				resourceKeyDeploymentStepAction: &schema.Schema{
+					Type:        schema.TypeList,
+					Required:    true,
+					Description: "The actions performed by the step.",
+					Elem: &schema.Resource{
+						Schema: schemaDeploymentAction(),
+					},
+				},
+			},
+		},
+	}
+}
+
+// The schema for a deployment step's actions.
+func schemaDeploymentAction() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		resourceKeyDeploymentActionID: &schema.Schema{
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "The action Id.",
+		},
+		resourceKeyDeploymentActionName: &schema.Schema{
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "The action name (must be unique within the deployment process).",
+		},
+		resourceKeyDeploymentActionType: &schema.Schema{
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "The action type (e.g. 'Octopus.TentaclePackage' or 'Octopus.Script').",
+		},
+		resourceKeyDeploymentActionProperties: &schema.Schema{
+			Type:        schema.TypeMap,
+			Optional:    true,
+			Description: "The action's properties (e.g. 'Octopus.Action.Script.ScriptBody'). Properties that Octopus adds to the action are ignored unless they are specified here.",
+		},
+		resourceKeyDeploymentActionSensitiveProperties: &schema.Schema{
+			Type:        schema.TypeMap,
+			Optional:    true,
+			Sensitive:   true,
+			Description: "The action's sensitive properties (e.g. passwords), which Octopus stores as sensitive values. Octopus never returns these values, so a sensitive property whose value was changed outside Terraform is not detected (but one that was removed, or replaced with a non-sensitive value, is).",
+		},
+		resourceKeyDeploymentActionPackage: &schema.Schema{
+			Type:        schema.TypeList,
+			Optional:    true,
+			Description: "The packages referenced by the action.",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					resourceKeyDeploymentActionPackageID: &schema.Schema{
+						Type:        schema.TypeString,
+						Computed:    true,
+						Description: "The package reference Id.",
+					},
+					resourceKeyDeploymentActionPackageName: &schema.Schema{
+						Type:        schema.TypeString,
+						Optional:    true,
+						Default:     "",
+						Description: "The package reference name (if not specified, this is the action's primary package).",
+					},
+					resourceKeyDeploymentActionPackagePackageID: &schema.Schema{
+						Type:        schema.TypeString,
+						Required:    true,
+						Description: "The Id of the package in its feed.",
+					},
+					resourceKeyDeploymentActionPackageFeed: &schema.Schema{
+						Type:        schema.TypeString,
+						Optional:    true,
+						Default:     builtInFeedID,
+						Description: "The Id of the feed that contains the package.",
+					},
+					resourceKeyDeploymentActionPackageAcquisitionLocation: &schema.Schema{
+						Type:        schema.TypeString,
+						Optional:    true,
+						Default:     "Server",
+						Description: "Where the package is acquired (e.g. 'Server', 'ExecutionTarget', or 'NotAcquired').",
+					},
+					resourceKeyDeploymentActionPackageProperties: &schema.Schema{
+						Type:        schema.TypeMap,
+						Optional:    true,
+						Description: "The package reference's properties (e.g. 'Extract'). Properties that Octopus adds to the package reference are ignored unless they are specified here.",
+					},
+				},
+			},
+		},
+		resourceKeyDeploymentActionEnvironments:         schemaDeploymentActionScope("The Ids of environments that the action runs in (if not specified, it runs in all environments)."),
+		resourceKeyDeploymentActionExcludedEnvironments: schemaDeploymentActionScope("The Ids of environments that the action is skipped in."),
+		resourceKeyDeploymentActionChannels:             schemaDeploymentActionScope("The Ids of channels whose releases run the action (if not specified, releases of all channels run it)."),
+		resourceKeyDeploymentActionTenantTags:           schemaDeploymentActionScope("The canonical names of tenant tags (e.g. 'Tier/Gold') of tenants for which the action runs."),
+		resourceKeyDeploymentActionContainer: &schema.Schema{
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Description: "The container image that the action runs in (if not specified, the action runs directly on the worker or deployment target).",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					resourceKeyDeploymentActionContainerImage: &schema.Schema{
+						Type:        schema.TypeString,
+						Required:    true,
+						Description: "The container image (e.g. 'octopusdeploy/worker-tools:ubuntu.18.04').",
+					},
+					resourceKeyDeploymentActionContainerFeed: &schema.Schema{
+						Type:        schema.TypeString,
+						Optional:    true,
+						Default:     "",
+						Description: "The Id of the container registry feed that contains the image.",
+					},
+				},
+			},
+		},
+		resourceKeyDeploymentActionWorkerPool: &schema.Schema{
+			Type:        schema.TypeString,
+			Optional:    true,
+			Default:     "",
+			Description: "The Id of the worker pool that runs the action (if not specified, the default worker pool is used).",
+		},
+		resourceKeyDeploymentActionIsDisabled: &schema.Schema{
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Is the action disabled?",
+		},
+		resourceKeyDeploymentActionIsRequired: &schema.Schema{
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Is the action required (i.e. it cannot be skipped when a deployment is created)?",
+		},
+	}
+}
+
+// The schema for one of a deployment action's scope dimensions.
+func schemaDeploymentActionScope(description string) *schema.Schema {
+	return &schema.Schema{
+		Type: schema.TypeSet,
+		Elem: &schema.Schema{
+			Type: schema.TypeString,
+		},
+		Set:         schema.HashString,
+		Optional:    true,
+		Description: description,
+	}
+}
+
+// Convert "step" blocks to Octopus deployment steps.
+//
+// Existing steps, actions, and package references are matched on name so that their Ids are preserved.
+func expandDeploymentSteps(stepBlocks []interface{}, existingSteps []octopus.DeploymentStep) ([]octopus.DeploymentStep, error) {
+	existingStepIDs := make(map[string]string)
+	existingActions := make(map[string]octopus.DeploymentAction)
+	for _, existingStep := range existingSteps {
+		existingStepIDs[existingStep.Name] = existingStep.ID
+		for _, existingAction := range existingStep.Actions {
+			existingActions[existingAction.Name] = existingAction
+		}
+	}
+
+	steps := make([]octopus.DeploymentStep, len(stepBlocks))
+	stepNames := make(map[string]bool, len(stepBlocks))
+	actionNames := make(map[string]bool)
+	for index, stepBlock := range stepBlocks {
+		properties := stepBlock.(map[string]interface{})
+
+		step := octopus.DeploymentStep{
+			Name:               properties[resourceKeyDeploymentStepName].(string),
+			Condition:          properties[resourceKeyDeploymentStepCondition].(string),
+			StartTrigger:       properties[resourceKeyDeploymentStepStartTrigger].(string),
+			PackageRequirement: properties[resourceKeyDeploymentStepPackageRequirement].(string),
+			Properties:         make(map[string]string),
+		}
+		if stepNames[step.Name] {
+			return nil, fmt.Errorf("Deployment process has more than one step named '%s'.", step.Name)
+		}
+		stepNames[step.Name] = true
+		step.ID = existingStepIDs[step.Name]
+
+		conditionExpression := properties[resourceKeyDeploymentStepConditionExpression].(string)
+		if step.Condition == stepConditionVariable {
+			if isEmpty(conditionExpression) {
+				return nil, fmt.Errorf("Step '%s' must specify %s when its condition is '%s'.", step.Name, resourceKeyDeploymentStepConditionExpression, stepConditionVariable)
+			}
+			step.Properties[stepPropertyConditionExpression] = conditionExpression
+		} else if !isEmpty(conditionExpression) {
+			return nil, fmt.Errorf("Step '%s' can only specify %s when its condition is '%s'.", step.Name, resourceKeyDeploymentStepConditionExpression, stepConditionVariable)
+		}
+
+		targetRoles := stringSetToList(properties[resourceKeyDeploymentStepTargetRoles].(*schema.Set))
+		if len(targetRoles) > 0 {
+			step.Properties[stepPropertyTargetRoles] = joinTargetRoles(targetRoles)
+		}
+		windowSize := properties[resourceKeyDeploymentStepWindowSize].(string)
+		if !isEmpty(windowSize) {
+			step.Properties[stepPropertyWindowSize] = windowSize
+		}
+
+		actionBlocks := properties[resourceKeyDeploymentStepAction].([]interface{})
+		if len(actionBlocks) == 0 {
+			return nil, fmt.Errorf("Step '%s' must have at least one %s.", step.Name, resourceKeyDeploymentStepAction)
+		}
+		step.Actions = make([]octopus.DeploymentAction, len(actionBlocks))
+		for actionIndex, actionBlock := range actionBlocks {
+			action, err := expandDeploymentAction(actionBlock.(map[string]interface{}), existingActions)
+			if err != nil {
+				return nil, err
+			}
+			if actionNames[action.Name] {
+				return nil, fmt.Errorf("Deployment process has more than one action named '%s'.", action.Name)
+			}
+			actionNames[action.Name] = true
+
+			step.Actions[actionIndex] = action
+		}
+
+		steps[index] = step
+	}
+
+	return steps, nil
+}
+
+// Convert an "action" block to an Octopus deployment action.
+func expandDeploymentAction(properties map[string]interface{}, existingActions map[string]octopus.DeploymentAction) (octopus.DeploymentAction, error) {
+	action := octopus.DeploymentAction{
+		Name:                 properties[resourceKeyDeploymentActionName].(string),
+		ActionType:           properties[resourceKeyDeploymentActionType].(string),
+		Properties:           stringMapFromProperties(properties[resourceKeyDeploymentActionProperties].(map[string]interface{})),
+		Environments:         stringSetToList(properties[resourceKeyDeploymentActionEnvironments].(*schema.Set)),
+		ExcludedEnvironments: stringSetToList(properties[resourceKeyDeploymentActionExcludedEnvironments].(*schema.Set)),
+		Channels:             stringSetToList(properties[resourceKeyDeploymentActionChannels].(*schema.Set)),
+		TenantTags:           stringSetToList(properties[resourceKeyDeploymentActionTenantTags].(*schema.Set)),
+		WorkerPoolID:         properties[resourceKeyDeploymentActionWorkerPool].(string),
+		IsDisabled:           properties[resourceKeyDeploymentActionIsDisabled].(bool),
+		IsRequired:           properties[resourceKeyDeploymentActionIsRequired].(bool),
+	}
+
+	sensitiveProperties := stringMapFromProperties(properties[resourceKeyDeploymentActionSensitiveProperties].(map[string]interface{}))
+	for key := range sensitiveProperties {
+		_, ok := action.Properties[key]
+		if ok {
+			return action, fmt.Errorf("Property '%s' of action '%s' cannot be specified in both %s and %s.", key, action.Name, resourceKeyDeploymentActionProperties, resourceKeyDeploymentActionSensitiveProperties)
+		}
+	}
+	action.SensitiveProperties = sensitiveProperties
+
+	existingPackageIDs := make(map[string]string)
+	existingAction, ok := existingActions[action.Name]
+	if ok {
+		action.ID = existingAction.ID
+		for _, existingPackage := range existingAction.Packages {
+			existingPackageIDs[existingPackage.Name] = existingPackage.ID
+		}
+	}
+
+	for _, environmentID := range action.Environments {
+		if containsString(action.ExcludedEnvironments, environmentID) {
+			return action, fmt.Errorf("Environment '%s' cannot be both included in and excluded from action '%s'.", environmentID, action.Name)
+		}
+	}
+
+	packageBlocks := properties[resourceKeyDeploymentActionPackage].([]interface{})
+	action.Packages = make([]octopus.PackageReference, len(packageBlocks))
+	packageNames := make(map[string]bool, len(packageBlocks))
+	for index, packageBlock := range packageBlocks {
+		packageProperties := packageBlock.(map[string]interface{})
+
+		packageReference := octopus.PackageReference{
+			Name:                packageProperties[resourceKeyDeploymentActionPackageName].(string),
+			PackageID:           packageProperties[resourceKeyDeploymentActionPackagePackageID].(string),
+			FeedID:              packageProperties[resourceKeyDeploymentActionPackageFeed].(string),
+			AcquisitionLocation: packageProperties[resourceKeyDeploymentActionPackageAcquisitionLocation].(string),
+			Properties:          stringMapFromProperties(packageProperties[resourceKeyDeploymentActionPackageProperties].(map[string]interface{})),
+		}
+		if packageNames[packageReference.Name] {
+			if isEmpty(packageReference.Name) {
+				return action, fmt.Errorf("Action '%s' can only have one primary (unnamed) package.", action.Name)
+			}
+
+			return action, fmt.Errorf("Action '%s' has more than one package named '%s'.", action.Name, packageReference.Name)
+		}
+		packageNames[packageReference.Name] = true
+		packageReference.ID = existingPackageIDs[packageReference.Name]
+
+		action.Packages[index] = packageReference
+	}
+
+	containerBlocks := properties[resourceKeyDeploymentActionContainer].([]interface{})
+	if len(containerBlocks) > 0 && containerBlocks[0] != nil {
+		containerProperties := containerBlocks[0].(map[string]interface{})
+		action.Container = &octopus.DeploymentActionContainer{
+			Image:  containerProperties[resourceKeyDeploymentActionContainerImage].(string),
+			FeedID: containerProperties[resourceKeyDeploymentActionContainerFeed].(string),
+		}
+	}
+
+	return action, nil
+}
+
+// managedActionProperties tracks the properties of a deployment action that are managed by the resource configuration.
+type managedActionProperties struct {
+	// The keys of the action's (non-sensitive) properties.
+	Properties map[string]bool
+
+	// The action's sensitive properties (whose values cannot be read back from Octopus).
+	SensitiveProperties map[string]string
+
+	// The keys of the properties of each of the action's package references, keyed by package reference name.
+	PackageProperties map[string]map[string]bool
+}
+
+// Get the properties managed by the configuration for each action in the specified "step" blocks, keyed by action name.
+func getManagedActionProperties(stepBlocks []interface{}) map[string]managedActionProperties {
+	managedProperties := make(map[string]managedActionProperties)
+	for _, stepBlock := range stepBlocks {
+		actionBlocks := stepBlock.(map[string]interface{})[resourceKeyDeploymentStepAction].([]interface{})
+		for _, actionBlock := range actionBlocks {
+			properties := actionBlock.(map[string]interface{})
+
+			managed := managedActionProperties{
+				Properties:          make(map[string]bool),
+				SensitiveProperties: stringMapFromProperties(properties[resourceKeyDeploymentActionSensitiveProperties].(map[string]interface{})),
+				PackageProperties:   make(map[string]map[string]bool),
+			}
+			for key := range properties[resourceKeyDeploymentActionProperties].(map[string]interface{}) {
+				managed.Properties[key] = true
+			}
+			for _, packageBlock := range properties[resourceKeyDeploymentActionPackage].([]interface{}) {
+				packageProperties := packageBlock.(map[string]interface{})
+
+				packagePropertyKeys := make(map[string]bool)
+				for key := range packageProperties[resourceKeyDeploymentActionPackageProperties].(map[string]interface{}) {
+					packagePropertyKeys[key] = true
+				}
+				managed.PackageProperties[packageProperties[resourceKeyDeploymentActionPackageName].(string)] = packagePropertyKeys
+			}
+
+			managedProperties[properties[resourceKeyDeploymentActionName].(string)] = managed
+		}
+	}
+
+	return managedProperties
+}
+
+// Convert Octopus deployment steps to "step" blocks.
+//
+// Actions that are not present in managedProperties (e.g. when the deployment process is imported) have all of their properties read.
+func flattenDeploymentSteps(steps []octopus.DeploymentStep, managedProperties map[string]managedActionProperties) []interface{} {
+	stepBlocks := make([]interface{}, len(steps))
+	for index, step := range steps {
+		actionBlocks := make([]interface{}, len(step.Actions))
+		for actionIndex, action := range step.Actions {
+			managed, ok := managedProperties[action.Name]
+			if !ok {
+				managed = managedActionProperties{}
+			}
+			actionBlocks[actionIndex] = flattenDeploymentAction(action, managed)
+		}
+
+		stepBlocks[index] = map[string]interface{}{
+			resourceKeyDeploymentStepID:                  step.ID,
+			resourceKeyDeploymentStepName:                step.Name,
+			resourceKeyDeploymentStepCondition:           step.Condition,
+			resourceKeyDeploymentStepConditionExpression: step.Properties[stepPropertyConditionExpression],
+			resourceKeyDeploymentStepStartTrigger:        step.StartTrigger,
+			resourceKeyDeploymentStepPackageRequirement:  step.PackageRequirement,
+			resourceKeyDeploymentStepTargetRoles:         stringListToSet(splitTargetRoles(step.Properties[stepPropertyTargetRoles])),
+			resourceKeyDeploymentStepWindowSize:          step.Properties[stepPropertyWindowSize],
+			resourceKeyDeploymentStepAction:              actionBlocks,
+		}
+	}
+
+	return stepBlocks
+}
+
+// Convert an Octopus deployment action to an "action" block.
+//
+// Only the properties managed by the configuration are included (unless managed.Properties is nil, in which case all non-sensitive properties are included).
+func flattenDeploymentAction(action octopus.DeploymentAction, managed managedActionProperties) map[string]interface{} {
+	properties := make(map[string]string)
+	sensitiveProperties := make(map[string]string)
+	for key, value := range action.Properties {
+		if managed.Properties == nil || managed.Properties[key] {
+			properties[key] = value
+		}
+	}
+	for key := range action.SensitiveProperties {
+		value, ok := managed.SensitiveProperties[key]
+		if ok {
+			sensitiveProperties[key] = value // Octopus does not return the actual value.
+		}
+	}
+
+	packageBlocks := make([]interface{}, len(action.Packages))
+	for index, packageReference := range action.Packages {
+		packageProperties := packageReference.Properties
+		if managed.PackageProperties != nil {
+			packageProperties = filterProperties(packageReference.Properties, managed.PackageProperties[packageReference.Name])
+		}
+
+		packageBlocks[index] = map[string]interface{}{
+			resourceKeyDeploymentActionPackageID:                  packageReference.ID,
+			resourceKeyDeploymentActionPackageName:                packageReference.Name,
+			resourceKeyDeploymentActionPackagePackageID:           packageReference.PackageID,
+			resourceKeyDeploymentActionPackageFeed:                packageReference.FeedID,
+			resourceKeyDeploymentActionPackageAcquisitionLocation: packageReference.AcquisitionLocation,
+			resourceKeyDeploymentActionPackageProperties:          stringMapToProperties(packageProperties),
+		}
+	}
+
+	containerBlocks := []interface{}{}
+	if action.Container != nil && !isEmpty(action.Container.Image) {
+		containerBlocks = append(containerBlocks, map[string]interface{}{
+			resourceKeyDeploymentActionContainerImage: action.Container.Image,
+			resourceKeyDeploymentActionContainerFeed:  action.Container.FeedID,
+		})
+	}
+
+	return map[string]interface{}{
+		resourceKeyDeploymentActionID:                   action.ID,
+		resourceKeyDeploymentActionName:                 action.Name,
+		resourceKeyDeploymentActionType:                 action.ActionType,
+		resourceKeyDeploymentActionProperties:           stringMapToProperties(properties),
+		resourceKeyDeploymentActionSensitiveProperties:  stringMapToProperties(sensitiveProperties),
+		resourceKeyDeploymentActionPackage:              packageBlocks,
+		resourceKeyDeploymentActionEnvironments:         stringListToSet(action.Environments),
+		resourceKeyDeploymentActionExcludedEnvironments: stringListToSet(action.ExcludedEnvironments),
+		resourceKeyDeploymentActionChannels:             stringListToSet(action.Channels),
+		resourceKeyDeploymentActionTenantTags:           stringListToSet(action.TenantTags),
+		resourceKeyDeploymentActionContainer:            containerBlocks,
+		resourceKeyDeploymentActionWorkerPool:           action.WorkerPoolID,
+		resourceKeyDeploymentActionIsDisabled:           action.IsDisabled,
+		resourceKeyDeploymentActionIsRequired:           action.IsRequired,
+	}
+}
+
+// Octopus stores a step's target roles as a single comma-separated property value.
+func joinTargetRoles(targetRoles []string) string {
+	return strings.Join(targetRoles, ",")
+}
+
+// Split a step's comma-separated target roles property value.
+func splitTargetRoles(targetRoles string) []string {
+	if isEmpty(targetRoles) {
+		return []string{}
+	}
+
+	roles := strings.Split(targetRoles, ",")
+	for index, role := range roles {
+		roles[index] = strings.TrimSpace(role)
+	}
+
+	return roles
+}
+
+// Select the properties with the specified keys.
+func filterProperties(properties map[string]string, keys map[string]bool) map[string]string {
+	filteredProperties := make(map[string]string)
+	for key, value := range properties {
+		if keys[key] {
+			filteredProperties[key] = value
+		}
+	}
+
+	return filteredProperties
+}
+
+// Validate a step's window size (if specified).
+func validateWindowSize(value interface{}, key string) (warnings []string, errors []error) {
+	windowSize := value.(string)
+	if isEmpty(windowSize) {
+		return
+	}
+
+	size, err := strconv.Atoi(windowSize)
+	if err != nil || size < 1 {
+		errors = append(errors, fmt.Errorf("Invalid value '%s' for property '%s' (must be a positive integer).", windowSize, key))
+	}
+
+	return
+}
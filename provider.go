@@ -4,7 +4,7 @@ import (
 	"fmt"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
-	"octopus"
+	"log"
 	"os"
 )
 
@@ -16,7 +16,8 @@ func Provider() terraform.ResourceProvider {
 			"server_url": &schema.Schema{
 				Type:        schema.TypeString,
 				Required:    true,
-				Description: "The base URL of the Octopus Deploy server.",
+				DefaultFunc: schema.MultiEnvDefaultFunc([]string{"OCTOPUS_URL", "OCTOPUS_SERVER_URL"}, nil),
+				Description: "The base URL of the Octopus Deploy server (if not specified, then the OCTOPUS_URL or OCTOPUS_SERVER_URL environment variable will be used).",
 			},
 			"api_key": &schema.Schema{
 				Type:        schema.TypeString,
@@ -25,19 +26,119 @@ func Provider() terraform.ResourceProvider {
 				Default:     "",
 				Description: "The API key used to authenticate to the Octopus Deploy API (if not specified, then the OCTOPUS_API_KEY environment variable will be used).",
 			},
+			"access_token": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				Default:     "",
+				Description: "A bearer token (e.g. an OIDC access token) used to authenticate to the Octopus Deploy API instead of an API key (if neither this nor api_key is specified, then the OCTOPUS_ACCESS_TOKEN environment variable will be used if OCTOPUS_API_KEY is not present).",
+			},
+			"space_id": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				DefaultFunc:   schema.EnvDefaultFunc("OCTOPUS_SPACE_ID", ""),
+				ConflictsWith: []string{"space_name"},
+				Description:   "The Id of the space used by resources and data-sources that do not specify one (if not specified, the server's default space is used).",
+			},
+			"space_name": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				DefaultFunc:   schema.EnvDefaultFunc("OCTOPUS_SPACE", ""),
+				ConflictsWith: []string{"space_id"},
+				Description:   "The name of the space used by resources and data-sources that do not specify one (if not specified, the server's default space is used).",
+			},
+			"ca_certificate_file": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				DefaultFunc:   schema.EnvDefaultFunc("OCTOPUS_CA_CERTIFICATE_FILE", ""),
+				ConflictsWith: []string{"ca_certificate_pem"},
+				Description:   "The path of a file containing one or more PEM-encoded CA certificates to trust (in addition to the system's CA certificates) when connecting to the Octopus Deploy server.",
+			},
+			"ca_certificate_pem": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				Default:       "",
+				ConflictsWith: []string{"ca_certificate_file"},
+				Description:   "One or more PEM-encoded CA certificates to trust (in addition to the system's CA certificates) when connecting to the Octopus Deploy server.",
+			},
+			"client_certificate_file": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("OCTOPUS_CLIENT_CERTIFICATE_FILE", ""),
+				Description: "The path of a file containing the PEM-encoded client certificate used to authenticate to the Octopus Deploy server (requires client_key_file).",
+			},
+			"client_key_file": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("OCTOPUS_CLIENT_KEY_FILE", ""),
+				Description: "The path of a file containing the PEM-encoded private key for the client certificate (requires client_certificate_file).",
+			},
+			"insecure_skip_verify": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("OCTOPUS_INSECURE_SKIP_VERIFY", false),
+				Description: "Skip verification of the Octopus Deploy server's certificate? Not recommended; prefer ca_certificate_file or ca_certificate_pem.",
+			},
+			"proxy_url": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+				Description: "The URL of the HTTP(S) proxy used to connect to the Octopus Deploy server (if not specified, the HTTP_PROXY, HTTPS_PROXY, and NO_PROXY environment variables are used).",
+			},
+			"timeout": &schema.Schema{
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     0,
+				Description: "The timeout (in seconds) for each request to the Octopus Deploy server, including any retries (0 means no timeout).",
+			},
+			"retry_max_attempts": &schema.Schema{
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     5,
+				Description: "The maximum number of attempts made for each request to the Octopus Deploy server (1 disables retries). Requests are retried if the server responds with 429, or, for idempotent requests, with 502, 503, or 504. Read-only requests are also retried if the server cannot be reached.",
+			},
+			"retry_min_backoff": &schema.Schema{
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     1,
+				Description: "The delay (in seconds) before the first retry of a failed request (subsequent delays double, up to retry_max_backoff).",
+			},
+			"retry_max_backoff": &schema.Schema{
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     30,
+				Description: "The maximum delay (in seconds) between retries of a failed request (unless the server requests a longer delay via the Retry-After header).",
+			},
+			"retry_max_wait": &schema.Schema{
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     300,
+				Description: "The maximum delay (in seconds) that will be honoured when the server requests a longer delay before retrying (via the Retry-After header).",
+			},
 		},
 
 		// Provider resource definitions
 		ResourcesMap: map[string]*schema.Resource{
-			"octopus_environment": resourceEnvironment(),
-			"octopus_variable":    resourceVariable(),
+			"octopus_channel":            resourceChannel(),
+			"octopus_deployment_process": resourceDeploymentProcess(),
+			"octopus_environment":        resourceEnvironment(),
+			"octopus_lifecycle":          resourceLifecycle(),
+			"octopus_project":            resourceProject(),
+			"octopus_project_group":      resourceProjectGroup(),
+			"octopus_project_variables":  resourceProjectVariables(),
+			"octopus_space":              resourceSpace(),
+			"octopus_variable":           resourceVariable(),
 		},
 
 		DataSourcesMap: map[string]*schema.Resource{
-			"octopus_environment": datasourceEnvironment(),
-			"octopus_machine":     datasourceMachine(),
-			"octopus_project":     datasourceProject(),
-			"octopus_variable":    datasourceVariable(),
+			"octopus_environment":   datasourceEnvironment(),
+			"octopus_environments":  datasourceEnvironments(),
+			"octopus_machine":       datasourceMachine(),
+			"octopus_machines":      datasourceMachines(),
+			"octopus_project":       datasourceProject(),
+			"octopus_project_group": datasourceProjectGroup(),
+			"octopus_projects":      datasourceProjects(),
+			"octopus_variable":      datasourceVariable(),
 		},
 
 		// Provider configuration
@@ -46,26 +147,66 @@ func Provider() terraform.ResourceProvider {
 }
 
 // Configure the provider.
-// Returns the provider's compute API client.
+// Returns the provider state (from which API clients can be obtained).
 func configureProvider(providerSettings *schema.ResourceData) (interface{}, error) {
 	server := providerSettings.Get("server_url").(string)
 	apiKey := providerSettings.Get("api_key").(string)
+	accessToken := providerSettings.Get("access_token").(string)
 
-	if isEmpty(apiKey) {
+	if !isEmpty(apiKey) && !isEmpty(accessToken) {
+		return nil, fmt.Errorf("Only one of the 'api_key' or 'access_token' properties can be specified for the 'octopus' provider.")
+	}
+	// Environment variables are only used if neither credential is configured explicitly.
+	if isEmpty(apiKey) && isEmpty(accessToken) {
 		apiKey = os.Getenv("OCTOPUS_API_KEY")
 	}
-	if isEmpty(apiKey) {
-		return nil, fmt.Errorf("The 'api_key' property was not specified for the 'octopus' provider, and the 'OCTOPUS_API_KEY' environment variable is not present. Please supply either one of these to configure the API key used to authenticate to Octopus Deploy.")
+	if isEmpty(apiKey) && isEmpty(accessToken) {
+		accessToken = os.Getenv("OCTOPUS_ACCESS_TOKEN")
+	}
+	if isEmpty(apiKey) && isEmpty(accessToken) {
+		return nil, fmt.Errorf("Neither the 'api_key' nor the 'access_token' property was specified for the 'octopus' provider, and the 'OCTOPUS_API_KEY' and 'OCTOPUS_ACCESS_TOKEN' environment variables are not present. Please supply one of these to configure the credentials used to authenticate to Octopus Deploy.")
 	}
 
-	var (
-		client *octopus.Client
-		err    error
-	)
-	client, err = octopus.NewClientWithAPIKey(server, apiKey)
+	transportSettings, err := getHTTPTransportSettings(providerSettings)
 	if err != nil {
 		return nil, err
 	}
+	if transportSettings.InsecureSkipVerify {
+		log.Printf("[WARN] Verification of the Octopus Deploy server's certificate is disabled.")
+	}
+
+	transport, err := newHTTPTransport(transportSettings)
+	if err != nil {
+		return nil, err
+	}
+
+	providerState := newProviderState(server, &authTransport{
+		apiKey:      apiKey,
+		accessToken: accessToken,
+		next:        newRetryTransport(transportSettings.RetryPolicy, newTraceTransport(transport)),
+	})
+	providerState.timeout = transportSettings.Timeout
+
+	// Verify that we can create a client for the server.
+	serverClient, err := providerState.ServerClient()
+	if err != nil {
+		return nil, err
+	}
+
+	spaceID := providerSettings.Get("space_id").(string)
+	spaceName := providerSettings.Get("space_name").(string)
+	if !isEmpty(spaceID) && !isEmpty(spaceName) {
+		return nil, fmt.Errorf("Only one of the 'space_id' or 'space_name' properties can be specified for the 'octopus' provider.")
+	}
+	if !isEmpty(spaceName) {
+		spaceID, err = findSpaceIDByName(serverClient, spaceName)
+		if err != nil {
+			return nil, err
+		}
+
+		log.Printf("Resolved space '%s' to '%s'.", spaceName, spaceID)
+	}
+	providerState.defaultSpaceID = spaceID
 
-	return client, nil
+	return providerState, nil
 }
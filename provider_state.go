@@ -0,0 +1,198 @@
+package main
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+	"log"
+	"net/http"
+	"octopus"
+	"sync"
+	"time"
+)
+
+// providerState holds the provider's state (API clients, locks, etc).
+type providerState struct {
+	// The base URL of the Octopus Deploy server.
+	serverURL string
+
+	// The (authenticated) transport used by API clients.
+	transport http.RoundTripper
+
+	// The timeout for each request to the server (0 for no timeout).
+	timeout time.Duration
+
+	// The Id of the space used by resources and data-sources that do not specify one (empty for the server's default space).
+	defaultSpaceID string
+
+	// The Id of the server's default space (looked up the first time it is needed).
+	serverDefaultSpaceID   string
+	serverDefaultSpaceOnce *sync.Once
+
+	// API clients, keyed by space Id.
+	clients   map[string]*octopus.Client
+	stateLock *sync.Mutex
+
+	// Coordinates concurrent writes to variable sets.
+	variableSets *variableSetCoordinator
+
+	// Serialises read-modify-write operations on project groups, keyed by space Id and project group Id.
+	projectGroupLocks map[string]*sync.Mutex
+}
+
+// projectGroupMutation represents a change to a project group.
+type projectGroupMutation func(projectGroup *octopus.ProjectGroup) error
+
+func newProviderState(serverURL string, transport http.RoundTripper) *providerState {
+	return &providerState{
+		serverURL:              serverURL,
+		transport:              transport,
+		clients:                make(map[string]*octopus.Client),
+		stateLock:              &sync.Mutex{},
+		variableSets:           newVariableSetCoordinator(),
+		projectGroupLocks:      make(map[string]*sync.Mutex),
+		serverDefaultSpaceOnce: &sync.Once{},
+	}
+}
+
+// ClientForSpace retrieves the Octopus Deploy API client for the specified space.
+//
+// If spaceID is empty, the provider's default space is used.
+func (state *providerState) ClientForSpace(spaceID string) (*octopus.Client, error) {
+	return state.clientForSpace(state.resolveSpaceID(spaceID))
+}
+
+// ServerClient retrieves an Octopus Deploy API client whose requests are not routed to any particular space.
+//
+// This is used to manage the spaces themselves.
+func (state *providerState) ServerClient() (*octopus.Client, error) {
+	return state.clientForSpace("")
+}
+
+// ClientForResource retrieves the Octopus Deploy API client for the space targeted by a resource or data-source.
+//
+// The resource or data-source must have a "space_id" property.
+func (state *providerState) ClientForResource(data *schema.ResourceData) (*octopus.Client, error) {
+	return state.ClientForSpace(data.Get(resourceKeySpaceID).(string))
+}
+
+// UpdateVariableSet applies a mutation to the variable set of the specified owner (project or library variable set).
+//
+// Concurrent mutations targeting the same owner are serialised and, where possible, coalesced into a single update.
+func (state *providerState) UpdateVariableSet(owner variableOwner, mutation variableSetMutation) (*octopus.VariableSet, error) {
+	// Owners in the default space must be serialised together, whether or not they specify its Id.
+	owner.SpaceID = state.resolveSpaceID(owner.SpaceID)
+
+	client, err := state.ClientForSpace(owner.SpaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	return state.variableSets.Update(client, owner, mutation)
+}
+
+// UpdateProjectGroup applies a mutation to the specified project group.
+//
+// Project groups are modified both by their own resource and by environment resources (which add themselves to the group's environments), so concurrent mutations targeting the same project group are serialised.
+// Returns nil (with no error) if the project group does not exist.
+func (state *providerState) UpdateProjectGroup(spaceID string, projectGroupID string, mutation projectGroupMutation) (*octopus.ProjectGroup, error) {
+	spaceID = state.resolveSpaceID(spaceID)
+
+	client, err := state.clientForSpace(spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	writeLock := state.projectGroupLock(spaceID, projectGroupID)
+	writeLock.Lock()
+	defer writeLock.Unlock()
+
+	projectGroup, err := client.GetProjectGroup(projectGroupID)
+	if err != nil {
+		return nil, err
+	}
+	if projectGroup == nil {
+		return nil, nil
+	}
+
+	err = mutation(projectGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.UpdateProjectGroup(projectGroup)
+}
+
+// Get or create the lock that serialises writes to the specified project group.
+func (state *providerState) projectGroupLock(spaceID string, projectGroupID string) *sync.Mutex {
+	state.stateLock.Lock()
+	defer state.stateLock.Unlock()
+
+	key := spaceID + "/" + projectGroupID
+	writeLock, ok := state.projectGroupLocks[key]
+	if !ok {
+		writeLock = &sync.Mutex{}
+		state.projectGroupLocks[key] = writeLock
+	}
+
+	return writeLock
+}
+
+// Resolve the Id of the space targeted by a resource or data-source.
+//
+// If spaceID is empty, the provider's default space is used (or, if that is not specified, the server's default space).
+// Returns an empty string only if the server does not support spaces.
+func (state *providerState) resolveSpaceID(spaceID string) string {
+	if !isEmpty(spaceID) {
+		return spaceID
+	}
+	if !isEmpty(state.defaultSpaceID) {
+		return state.defaultSpaceID
+	}
+
+	state.serverDefaultSpaceOnce.Do(func() {
+		serverClient, err := state.ServerClient()
+		if err == nil {
+			state.serverDefaultSpaceID, err = findDefaultSpaceID(serverClient)
+		}
+		if err != nil {
+			log.Printf("[WARN] Unable to determine the server's default space (requests will not be routed to a specific space): %s", err.Error())
+
+			return
+		}
+
+		log.Printf("The server's default space is '%s'.", state.serverDefaultSpaceID)
+	})
+
+	return state.serverDefaultSpaceID
+}
+
+// Get or create the API client for the specified space (or, if spaceID is empty, an API client that is not routed to any particular space).
+func (state *providerState) clientForSpace(spaceID string) (*octopus.Client, error) {
+	state.stateLock.Lock()
+	defer state.stateLock.Unlock()
+
+	client, ok := state.clients[spaceID]
+	if ok {
+		return client, nil
+	}
+
+	log.Printf("Create API client for space '%s'.", spaceID)
+
+	transport := state.transport
+	if !isEmpty(spaceID) {
+		transport = &spaceTransport{
+			spaceID: spaceID,
+			next:    transport,
+		}
+	}
+
+	client, err := octopus.NewClient(state.serverURL, &http.Client{
+		Transport: transport,
+		Timeout:   state.timeout,
+	})
+	if err != nil {
+		return nil, err
+	}
+	state.clients[spaceID] = client
+
+	return client, nil
+}
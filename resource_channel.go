@@ -0,0 +1,396 @@
+package main
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"log"
+	"octopus"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	resourceKeyChannelProjectID                  = "project"
+	resourceKeyChannelName                       = "name"
+	resourceKeyChannelDescription                = "description"
+	resourceKeyChannelLifecycleID                = "lifecycle_id"
+	resourceKeyChannelIsDefault                  = "is_default"
+	resourceKeyChannelTenantTags                 = "tenant_tags"
+	resourceKeyChannelRule                       = "rule"
+	resourceKeyChannelRuleID                     = "id"
+	resourceKeyChannelRuleVersionRange           = "version_range"
+	resourceKeyChannelRuleTag                    = "tag"
+	resourceKeyChannelRuleActionPackage          = "action_package"
+	resourceKeyChannelRuleActionPackageAction    = "deployment_action"
+	resourceKeyChannelRuleActionPackageReference = "package_reference"
+)
+
+func resourceChannel() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceChannelCreate,
+		Read:   resourceChannelRead,
+		Update: resourceChannelUpdate,
+		Delete: resourceChannelDelete,
+		Exists: resourceChannelExists,
+		Importer: &schema.ResourceImporter{
+			State: importStateInSpace,
+		},
+
+		Schema: map[string]*schema.Schema{
+			resourceKeySpaceID: schemaSpaceID(true),
+			resourceKeyChannelProjectID: &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The Id of the project that the channel belongs to.",
+			},
+			resourceKeyChannelName: &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The channel name.",
+			},
+			resourceKeyChannelDescription: &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+				Description: "The channel description.",
+			},
+			resourceKeyChannelLifecycleID: &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+				Description: "The Id of the lifecycle used by the channel's releases (if not specified, the project's lifecycle is used).",
+			},
+			resourceKeyChannelIsDefault: &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Is this the project's default channel? Making a channel the default clears is_default on the project's previous default channel, so if that channel is also managed by Terraform, set its is_default to false as well (otherwise it will show a difference on the next plan). Octopus does not allow the default channel to be deleted, so destroying it only removes it from Terraform state.",
+			},
+			resourceKeyChannelTenantTags: &schema.Schema{
+				Type: schema.TypeSet,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Set:         schema.HashString,
+				Optional:    true,
+				Description: "The canonical names of tenant tags (e.g. 'Tier/Gold') that restrict which tenants the channel's releases can be deployed to.",
+			},
+			resourceKeyChannelRule: &schema.Schema{
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Rules that restrict which package versions can be used in the channel's releases.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						resourceKeyChannelRuleID: &schema.Schema{
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The rule Id (rules are matched to existing rules on their action_package blocks, so changing a rule's packages replaces it with a new rule).",
+						},
+						resourceKeyChannelRuleVersionRange: &schema.Schema{
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "",
+							ValidateFunc: validateChannelRuleVersionRange,
+							Description:  "The NuGet version range that package versions must fall within (e.g. '[1.0,2.0)').",
+						},
+						resourceKeyChannelRuleTag: &schema.Schema{
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "",
+							ValidateFunc: validateChannelRuleTag,
+							Description:  "A regular expression that the pre-release tags of package versions must match (e.g. '^$' for no pre-release tag).",
+						},
+						resourceKeyChannelRuleActionPackage: &schema.Schema{
+							Type:        schema.TypeList,
+							Required:    true,
+							Description: "The packages (referenced by deployment actions) to which the rule applies.",
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									resourceKeyChannelRuleActionPackageAction: &schema.Schema{
+										Type:        schema.TypeString,
+										Required:    true,
+										Description: "The name of the deployment action.",
+									},
+									resourceKeyChannelRuleActionPackageReference: &schema.Schema{
+										Type:        schema.TypeString,
+										Optional:    true,
+										Default:     "",
+										Description: "The name of the action's package reference (if not specified, the action's primary package).",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Create a channel resource.
+func resourceChannelCreate(data *schema.ResourceData, provider interface{}) error {
+	channel := &octopus.Channel{
+		ProjectID: data.Get(resourceKeyChannelProjectID).(string),
+	}
+	err := expandChannel(data, channel)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Create channel named '%s' for project '%s'.", channel.Name, channel.ProjectID)
+
+	client, err := provider.(*providerState).ClientForResource(data)
+	if err != nil {
+		return err
+	}
+
+	channel, err = client.CreateChannel(channel)
+	if err != nil {
+		return err
+	}
+
+	data.SetId(channel.ID)
+
+	return resourceChannelRead(data, provider)
+}
+
+// Read a channel resource.
+func resourceChannelRead(data *schema.ResourceData, provider interface{}) error {
+	id := data.Id()
+	name := data.Get(resourceKeyChannelName).(string)
+
+	log.Printf("Read channel '%s' (name = '%s').", id, name)
+
+	client, err := provider.(*providerState).ClientForResource(data)
+	if err != nil {
+		return err
+	}
+
+	channel, err := client.GetChannel(id)
+	if err != nil {
+		return err
+	}
+	if channel == nil {
+		// Channel has been deleted.
+		data.SetId("")
+
+		return nil
+	}
+
+	data.Set(resourceKeyChannelProjectID, channel.ProjectID)
+	data.Set(resourceKeyChannelName, channel.Name)
+	data.Set(resourceKeyChannelDescription, channel.Description)
+	data.Set(resourceKeyChannelLifecycleID, channel.LifecycleID)
+	data.Set(resourceKeyChannelIsDefault, channel.IsDefault)
+	data.Set(resourceKeyChannelTenantTags, stringListToSet(channel.TenantTags))
+	data.Set(resourceKeyChannelRule, flattenChannelRules(channel.Rules))
+
+	return nil
+}
+
+// Update a channel resource.
+func resourceChannelUpdate(data *schema.ResourceData, provider interface{}) error {
+	id := data.Id()
+
+	log.Printf("Update channel '%s'.", id)
+
+	client, err := provider.(*providerState).ClientForResource(data)
+	if err != nil {
+		return err
+	}
+
+	channel, err := client.GetChannel(id)
+	if err != nil {
+		return err
+	}
+	if channel == nil {
+		// Channel has been deleted.
+		data.SetId("")
+
+		return nil
+	}
+
+	err = expandChannel(data, channel)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.UpdateChannel(channel)
+	if err != nil {
+		return err
+	}
+
+	return resourceChannelRead(data, provider)
+}
+
+// Delete a channel resource.
+func resourceChannelDelete(data *schema.ResourceData, provider interface{}) error {
+	id := data.Id()
+	name := data.Get(resourceKeyChannelName).(string)
+
+	log.Printf("Delete channel '%s' (name = '%s').", id, name)
+
+	client, err := provider.(*providerState).ClientForResource(data)
+	if err != nil {
+		return err
+	}
+
+	channel, err := client.GetChannel(id)
+	if err != nil {
+		return err
+	}
+	if channel == nil {
+		log.Printf("Channel '%s' not found; treating it as already deleted.", id)
+
+		return nil
+	}
+	if channel.IsDefault {
+		// Octopus does not allow a project's default channel to be deleted; it is deleted along with the project.
+		log.Printf("[WARN] Channel '%s' (name = '%s') is the default channel for project '%s' and cannot be deleted; removing it from Terraform state only (it will be deleted when the project is deleted).", id, channel.Name, channel.ProjectID)
+		data.SetId("")
+
+		return nil
+	}
+
+	return client.DeleteChannel(id)
+}
+
+// Determine whether a channel resource exists.
+func resourceChannelExists(data *schema.ResourceData, provider interface{}) (exists bool, err error) {
+	id := data.Id()
+
+	log.Printf("Check if channel '%s' exists.", id)
+
+	var client *octopus.Client
+	client, err = provider.(*providerState).ClientForResource(data)
+	if err != nil {
+		return
+	}
+
+	var channel *octopus.Channel
+	channel, err = client.GetChannel(id)
+	exists = channel != nil
+
+	return
+}
+
+// Apply the channel settings from the resource configuration to a channel.
+func expandChannel(data *schema.ResourceData, channel *octopus.Channel) error {
+	channel.Name = data.Get(resourceKeyChannelName).(string)
+	channel.Description = data.Get(resourceKeyChannelDescription).(string)
+	channel.LifecycleID = data.Get(resourceKeyChannelLifecycleID).(string)
+	channel.IsDefault = data.Get(resourceKeyChannelIsDefault).(bool)
+	channel.TenantTags = stringSetToList(data.Get(resourceKeyChannelTenantTags).(*schema.Set))
+
+	rules, err := expandChannelRules(data.Get(resourceKeyChannelRule).([]interface{}), channel.Rules)
+	if err != nil {
+		return err
+	}
+	channel.Rules = rules
+
+	return nil
+}
+
+// Convert "rule" blocks to Octopus channel version rules.
+//
+// Rules have no name, so existing rules are matched on the packages they apply to (rather than their position) to preserve their Ids.
+func expandChannelRules(ruleBlocks []interface{}, existingRules []octopus.ChannelVersionRule) ([]octopus.ChannelVersionRule, error) {
+	existingRuleIDs := make(map[string][]string)
+	for _, existingRule := range existingRules {
+		key := channelRuleKey(existingRule.ActionPackages)
+		existingRuleIDs[key] = append(existingRuleIDs[key], existingRule.ID)
+	}
+
+	rules := make([]octopus.ChannelVersionRule, len(ruleBlocks))
+	for index, ruleBlock := range ruleBlocks {
+		properties := ruleBlock.(map[string]interface{})
+
+		rule := octopus.ChannelVersionRule{
+			VersionRange: properties[resourceKeyChannelRuleVersionRange].(string),
+			Tag:          properties[resourceKeyChannelRuleTag].(string),
+		}
+		if isEmpty(rule.VersionRange) && isEmpty(rule.Tag) {
+			return nil, fmt.Errorf("Channel rule %d must specify a %s and / or a %s.", index+1, resourceKeyChannelRuleVersionRange, resourceKeyChannelRuleTag)
+		}
+
+		actionPackageBlocks := properties[resourceKeyChannelRuleActionPackage].([]interface{})
+		if len(actionPackageBlocks) == 0 {
+			return nil, fmt.Errorf("Channel rule %d must specify at least one %s.", index+1, resourceKeyChannelRuleActionPackage)
+		}
+		rule.ActionPackages = make([]octopus.DeploymentActionPackage, len(actionPackageBlocks))
+		for actionPackageIndex, actionPackageBlock := range actionPackageBlocks {
+			actionPackageProperties := actionPackageBlock.(map[string]interface{})
+			rule.ActionPackages[actionPackageIndex] = octopus.DeploymentActionPackage{
+				DeploymentAction: actionPackageProperties[resourceKeyChannelRuleActionPackageAction].(string),
+				PackageReference: actionPackageProperties[resourceKeyChannelRuleActionPackageReference].(string),
+			}
+		}
+
+		key := channelRuleKey(rule.ActionPackages)
+		if ids := existingRuleIDs[key]; len(ids) > 0 {
+			rule.ID = ids[0]
+			existingRuleIDs[key] = ids[1:]
+		}
+
+		rules[index] = rule
+	}
+
+	return rules, nil
+}
+
+// Get the key used to match a channel rule to an existing rule (its action packages, in a consistent order).
+func channelRuleKey(actionPackages []octopus.DeploymentActionPackage) string {
+	keys := make([]string, len(actionPackages))
+	for index, actionPackage := range actionPackages {
+		keys[index] = actionPackage.DeploymentAction + "/" + actionPackage.PackageReference
+	}
+	sort.Strings(keys)
+
+	return strings.Join(keys, ",")
+}
+
+// Convert Octopus channel version rules to "rule" blocks.
+func flattenChannelRules(rules []octopus.ChannelVersionRule) []interface{} {
+	ruleBlocks := make([]interface{}, len(rules))
+	for index, rule := range rules {
+		actionPackageBlocks := make([]interface{}, len(rule.ActionPackages))
+		for actionPackageIndex, actionPackage := range rule.ActionPackages {
+			actionPackageBlocks[actionPackageIndex] = map[string]interface{}{
+				resourceKeyChannelRuleActionPackageAction:    actionPackage.DeploymentAction,
+				resourceKeyChannelRuleActionPackageReference: actionPackage.PackageReference,
+			}
+		}
+
+		ruleBlocks[index] = map[string]interface{}{
+			resourceKeyChannelRuleID:            rule.ID,
+			resourceKeyChannelRuleVersionRange:  rule.VersionRange,
+			resourceKeyChannelRuleTag:           rule.Tag,
+			resourceKeyChannelRuleActionPackage: actionPackageBlocks,
+		}
+	}
+
+	return ruleBlocks
+}
+
+// Validate a channel rule's version range (if specified).
+func validateChannelRuleVersionRange(value interface{}, key string) (warnings []string, errors []error) {
+	if isEmpty(value.(string)) {
+		return
+	}
+
+	return validateVersionRange(value, key)
+}
+
+// Validate a channel rule's pre-release tag pattern.
+//
+// Octopus evaluates the pattern as a .NET regular expression, which supports some constructs (e.g. lookarounds) that Go does not, so patterns that Go cannot parse only generate a warning.
+func validateChannelRuleTag(value interface{}, key string) (warnings []string, errors []error) {
+	_, err := regexp.Compile(value.(string))
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("Unable to verify the regular expression for property '%s': %s", key, err.Error()))
+	}
+
+	return
+}
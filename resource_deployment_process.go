@@ -0,0 +1,241 @@
+package main
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"log"
+	"net/http"
+	"octopus"
+)
+
+const (
+	resourceKeyDeploymentProcessProjectID = "project"
+	resourceKeyDeploymentProcessVersion   = "version"
+	resourceKeyDeploymentProcessStep      = "step"
+)
+
+func resourceDeploymentProcess() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceDeploymentProcessCreate,
+		Read:   resourceDeploymentProcessRead,
+		Update: resourceDeploymentProcessUpdate,
+		Delete: resourceDeploymentProcessDelete,
+		Exists: resourceDeploymentProcessExists,
+		Importer: &schema.ResourceImporter{
+			State: importStateInSpace,
+		},
+
+		Schema: map[string]*schema.Schema{
+			resourceKeySpaceID: schemaSpaceID(true),
+			resourceKeyDeploymentProcessProjectID: &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The Id of the project whose deployment process is managed by this resource.",
+			},
+			resourceKeyDeploymentProcessVersion: &schema.Schema{
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The version of the deployment process (incremented by Octopus each time the process is modified).",
+			},
+			resourceKeyDeploymentProcessStep: schemaDeploymentSteps(),
+		},
+	}
+}
+
+// Create a deployment process resource.
+//
+// Every project already has a deployment process, so this replaces the steps in the project's existing process.
+func resourceDeploymentProcessCreate(data *schema.ResourceData, provider interface{}) error {
+	projectID := data.Get(resourceKeyDeploymentProcessProjectID).(string)
+
+	log.Printf("Create deployment process for project '%s'.", projectID)
+
+	client, err := provider.(*providerState).ClientForResource(data)
+	if err != nil {
+		return err
+	}
+
+	project, err := client.GetProject(projectID)
+	if err != nil {
+		return err
+	}
+	if project == nil {
+		return fmt.Errorf("Cannot find project '%s'.", projectID)
+	}
+
+	deploymentProcess, err := client.GetDeploymentProcess(project.DeploymentProcessID)
+	if err != nil {
+		return err
+	}
+	if deploymentProcess == nil {
+		return fmt.Errorf("Cannot find deployment process '%s' for project '%s'.", project.DeploymentProcessID, projectID)
+	}
+	if len(deploymentProcess.Steps) > 0 {
+		log.Printf("[WARN] Deployment process '%s' for project '%s' already has %d step(s); they will be replaced.", deploymentProcess.ID, projectID, len(deploymentProcess.Steps))
+	}
+
+	err = applyDeploymentProcess(data, client, deploymentProcess)
+	if err != nil {
+		return err
+	}
+
+	data.SetId(deploymentProcess.ID)
+
+	return resourceDeploymentProcessRead(data, provider)
+}
+
+// Read a deployment process resource.
+func resourceDeploymentProcessRead(data *schema.ResourceData, provider interface{}) error {
+	id := data.Id()
+
+	log.Printf("Read deployment process '%s'.", id)
+
+	client, err := provider.(*providerState).ClientForResource(data)
+	if err != nil {
+		return err
+	}
+
+	deploymentProcess, err := client.GetDeploymentProcess(id)
+	if err != nil {
+		return err
+	}
+	if deploymentProcess == nil {
+		// Project (and therefore its deployment process) has been deleted.
+		data.SetId("")
+
+		return nil
+	}
+
+	data.Set(resourceKeyDeploymentProcessProjectID, deploymentProcess.ProjectID)
+	data.Set(resourceKeyDeploymentProcessVersion, deploymentProcess.Version)
+	// Only read back the action properties that are managed by the configuration (Octopus adds others of its own).
+	managedProperties := getManagedActionProperties(data.Get(resourceKeyDeploymentProcessStep).([]interface{}))
+	data.Set(resourceKeyDeploymentProcessStep, flattenDeploymentSteps(deploymentProcess.Steps, managedProperties))
+
+	return nil
+}
+
+// Update a deployment process resource.
+func resourceDeploymentProcessUpdate(data *schema.ResourceData, provider interface{}) error {
+	id := data.Id()
+
+	log.Printf("Update deployment process '%s'.", id)
+
+	client, err := provider.(*providerState).ClientForResource(data)
+	if err != nil {
+		return err
+	}
+
+	deploymentProcess, err := client.GetDeploymentProcess(id)
+	if err != nil {
+		return err
+	}
+	if deploymentProcess == nil {
+		// Project (and therefore its deployment process) has been deleted.
+		data.SetId("")
+
+		return nil
+	}
+
+	// Don't overwrite changes made since the plan was created.
+	expectedVersion := data.Get(resourceKeyDeploymentProcessVersion).(int)
+	if deploymentProcess.Version != expectedVersion {
+		return fmt.Errorf("Deployment process '%s' has been modified outside of Terraform (expected version %d, but found version %d); run 'terraform plan' again to review the latest changes.", id, expectedVersion, deploymentProcess.Version)
+	}
+
+	err = applyDeploymentProcess(data, client, deploymentProcess)
+	if err != nil {
+		return err
+	}
+
+	return resourceDeploymentProcessRead(data, provider)
+}
+
+// Delete a deployment process resource.
+//
+// A project's deployment process cannot be deleted, so this removes all of its steps.
+func resourceDeploymentProcessDelete(data *schema.ResourceData, provider interface{}) error {
+	id := data.Id()
+
+	log.Printf("Delete deployment process '%s'.", id)
+
+	client, err := provider.(*providerState).ClientForResource(data)
+	if err != nil {
+		return err
+	}
+
+	deploymentProcess, err := client.GetDeploymentProcess(id)
+	if err != nil {
+		return err
+	}
+	if deploymentProcess == nil {
+		log.Printf("Deployment process '%s' not found; treating it as already deleted.", id)
+
+		return nil
+	}
+	if len(deploymentProcess.Steps) == 0 {
+		return nil // Nothing to do.
+	}
+
+	deploymentProcess.Steps = []octopus.DeploymentStep{}
+
+	return updateDeploymentProcess(client, deploymentProcess)
+}
+
+// Determine whether a deployment process resource exists.
+func resourceDeploymentProcessExists(data *schema.ResourceData, provider interface{}) (exists bool, err error) {
+	id := data.Id()
+
+	log.Printf("Check if deployment process '%s' exists.", id)
+
+	var client *octopus.Client
+	client, err = provider.(*providerState).ClientForResource(data)
+	if err != nil {
+		return
+	}
+
+	var deploymentProcess *octopus.DeploymentProcess
+	deploymentProcess, err = client.GetDeploymentProcess(id)
+	exists = deploymentProcess != nil
+
+	return
+}
+
+// Replace the steps in a deployment process with the steps declared in the resource configuration.
+func applyDeploymentProcess(data *schema.ResourceData, client *octopus.Client, deploymentProcess *octopus.DeploymentProcess) error {
+	steps, err := expandDeploymentSteps(data.Get(resourceKeyDeploymentProcessStep).([]interface{}), deploymentProcess.Steps)
+	if err != nil {
+		return err
+	}
+	deploymentProcess.Steps = steps
+
+	return updateDeploymentProcess(client, deploymentProcess)
+}
+
+// Write a deployment process back to Octopus.
+//
+// The whole process is replaced in a single request; Octopus rejects it if the process has been modified since it was retrieved (i.e. its Version has changed).
+func updateDeploymentProcess(client *octopus.Client, deploymentProcess *octopus.DeploymentProcess) error {
+	log.Printf("Write %d step(s) to deployment process '%s' (version %d).", len(deploymentProcess.Steps), deploymentProcess.ID, deploymentProcess.Version)
+
+	_, err := client.UpdateDeploymentProcess(deploymentProcess)
+	if err != nil {
+		if isDeploymentProcessConflict(err) {
+			return fmt.Errorf("Deployment process '%s' was modified by someone else while it was being updated; run 'terraform plan' again to review the latest changes: %s", deploymentProcess.ID, err.Error())
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// Determine whether an error indicates that a deployment process update was rejected because the process had been modified since it was retrieved.
+//
+// Octopus responds with 409 (Conflict) if the update's Version does not match the current version of the deployment process.
+func isDeploymentProcessConflict(err error) bool {
+	apiError, ok := err.(*octopus.APIError)
+
+	return ok && apiError.StatusCode == http.StatusConflict
+}
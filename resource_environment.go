@@ -1,15 +1,19 @@
 package main
 
 import (
+	"fmt"
 	"github.com/hashicorp/terraform/helper/schema"
 	"log"
 	"octopus"
 )
 
 const (
-	resourceKeyEnvironmentName          = "name"
-	resourceKeyEnvironmentDescription   = "description"
-	resourceKeyEnvironmentProjectGroups = "project_groups"
+	resourceKeyEnvironmentName                       = "name"
+	resourceKeyEnvironmentDescription                = "description"
+	resourceKeyEnvironmentProjectGroups              = "project_groups"
+	resourceKeyEnvironmentSortOrder                  = "sort_order"
+	resourceKeyEnvironmentUseGuidedFailure           = "use_guided_failure"
+	resourceKeyEnvironmentAllowDynamicInfrastructure = "allow_dynamic_infrastructure"
 )
 
 func resourceEnvironment() *schema.Resource {
@@ -19,8 +23,12 @@ func resourceEnvironment() *schema.Resource {
 		Update: resourceEnvironmentUpdate,
 		Delete: resourceEnvironmentDelete,
 		Exists: resourceEnvironmentExists,
+		Importer: &schema.ResourceImporter{
+			State: importStateInSpace,
+		},
 
 		Schema: map[string]*schema.Schema{
+			resourceKeySpaceID: schemaSpaceID(true),
 			resourceKeyEnvironmentName: &schema.Schema{
 				Type:        schema.TypeString,
 				Required:    true,
@@ -33,14 +41,31 @@ func resourceEnvironment() *schema.Resource {
 				Description: "The environment description.",
 			},
 			resourceKeyEnvironmentProjectGroups: &schema.Schema{
-				Type: schema.TypeList,
+				Type: schema.TypeSet,
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
 				},
+				Set:         schema.HashString,
+				Optional:    true,
+				Description: "The Ids of project groups associated with the environment (if not specified, the environment is not associated with any project groups).",
+			},
+			resourceKeyEnvironmentSortOrder: &schema.Schema{
+				Type:        schema.TypeInt,
+				Optional:    true,
 				Computed:    true,
+				Description: "The environment's sort order (determines the order in which environments are displayed).",
+			},
+			resourceKeyEnvironmentUseGuidedFailure: &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Use guided failure mode by default for deployments to the environment?",
+			},
+			resourceKeyEnvironmentAllowDynamicInfrastructure: &schema.Schema{
+				Type:        schema.TypeBool,
 				Optional:    true,
-				Default:     nil,
-				Description: "The Ids of project groups associated with the environment.",
+				Default:     false,
+				Description: "Allow deployments to create and manage deployment targets in the environment?",
 			},
 		},
 	}
@@ -50,19 +75,46 @@ func resourceEnvironment() *schema.Resource {
 func resourceEnvironmentCreate(data *schema.ResourceData, provider interface{}) error {
 	name := data.Get(resourceKeyEnvironmentName).(string)
 	description := data.Get(resourceKeyEnvironmentDescription).(string)
+	sortOrder := data.Get(resourceKeyEnvironmentSortOrder).(int)
 
 	log.Printf("Create environment named '%s'.", name)
 
-	client := provider.(*octopus.Client)
+	client, err := provider.(*providerState).ClientForResource(data)
+	if err != nil {
+		return err
+	}
 
-	environment, err := client.CreateEnvironment(name, description, 0)
+	environment, err := client.CreateEnvironment(name, description, sortOrder)
 	if err != nil {
 		return err
 	}
 
 	data.SetId(environment.ID)
 
-	return nil
+	useGuidedFailure := data.Get(resourceKeyEnvironmentUseGuidedFailure).(bool)
+	allowDynamicInfrastructure := data.Get(resourceKeyEnvironmentAllowDynamicInfrastructure).(bool)
+	if environment.UseGuidedFailure != useGuidedFailure || environment.AllowDynamicInfrastructure != allowDynamicInfrastructure {
+		environment.UseGuidedFailure = useGuidedFailure
+		environment.AllowDynamicInfrastructure = allowDynamicInfrastructure
+
+		environment, err = client.UpdateEnvironment(environment)
+		if err != nil {
+			return err
+		}
+	}
+
+	projectGroupIDs, ok := data.GetOk(resourceKeyEnvironmentProjectGroups)
+	if ok {
+		err = updateEnvironmentProjectGroups(provider.(*providerState), data.Get(resourceKeySpaceID).(string), environment.ID,
+			newStringSet(),
+			projectGroupIDs.(*schema.Set),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return resourceEnvironmentRead(data, provider)
 }
 
 // Read an environment resource.
@@ -72,7 +124,10 @@ func resourceEnvironmentRead(data *schema.ResourceData, provider interface{}) er
 
 	log.Printf("Read environment '%s' (name = '%s').", id, name)
 
-	client := provider.(*octopus.Client)
+	client, err := provider.(*providerState).ClientForResource(data)
+	if err != nil {
+		return err
+	}
 	environment, err := client.GetEnvironment(id)
 	if err != nil {
 		return err
@@ -87,6 +142,15 @@ func resourceEnvironmentRead(data *schema.ResourceData, provider interface{}) er
 
 	data.Set(resourceKeyEnvironmentName, environment.Name)
 	data.Set(resourceKeyEnvironmentDescription, environment.Description)
+	data.Set(resourceKeyEnvironmentSortOrder, environment.SortOrder)
+	data.Set(resourceKeyEnvironmentUseGuidedFailure, environment.UseGuidedFailure)
+	data.Set(resourceKeyEnvironmentAllowDynamicInfrastructure, environment.AllowDynamicInfrastructure)
+
+	projectGroupIDs, err := getEnvironmentProjectGroups(client, id)
+	if err != nil {
+		return err
+	}
+	data.Set(resourceKeyEnvironmentProjectGroups, stringListToSet(projectGroupIDs))
 
 	return nil
 }
@@ -97,33 +161,63 @@ func resourceEnvironmentUpdate(data *schema.ResourceData, provider interface{})
 
 	log.Printf("Update environment '%s'.", id)
 
-	if !(data.HasChange(resourceKeyEnvironmentName) || data.HasChange(resourceKeyEnvironmentDescription)) {
-		return nil // Nothing to do.
-	}
-
-	client := provider.(*octopus.Client)
-	environment, err := client.GetEnvironment(id)
+	client, err := provider.(*providerState).ClientForResource(data)
 	if err != nil {
 		return err
 	}
-	if environment != nil {
-		// Environment has been deleted.
-		data.SetId("")
 
-		return nil
+	environmentChanged := data.HasChange(resourceKeyEnvironmentName) ||
+		data.HasChange(resourceKeyEnvironmentDescription) ||
+		data.HasChange(resourceKeyEnvironmentSortOrder) ||
+		data.HasChange(resourceKeyEnvironmentUseGuidedFailure) ||
+		data.HasChange(resourceKeyEnvironmentAllowDynamicInfrastructure)
+
+	if environmentChanged {
+		environment, err := client.GetEnvironment(id)
+		if err != nil {
+			return err
+		}
+		if environment == nil {
+			// Environment has been deleted.
+			data.SetId("")
+
+			return nil
+		}
+
+		if data.HasChange(resourceKeyEnvironmentName) {
+			environment.Name = data.Get(resourceKeyEnvironmentName).(string)
+		}
+
+		if data.HasChange(resourceKeyEnvironmentDescription) {
+			environment.Description = data.Get(resourceKeyEnvironmentDescription).(string)
+		}
+
+		if data.HasChange(resourceKeyEnvironmentSortOrder) {
+			environment.SortOrder = data.Get(resourceKeyEnvironmentSortOrder).(int)
+		}
+
+		environment.UseGuidedFailure = data.Get(resourceKeyEnvironmentUseGuidedFailure).(bool)
+		environment.AllowDynamicInfrastructure = data.Get(resourceKeyEnvironmentAllowDynamicInfrastructure).(bool)
+
+		_, err = client.UpdateEnvironment(environment)
+		if err != nil {
+			return err
+		}
 	}
 
-	if data.HasChange(resourceKeyEnvironmentName) {
-		environment.Name = data.Get(resourceKeyEnvironmentName).(string)
-	}
+	if data.HasChange(resourceKeyEnvironmentProjectGroups) {
+		oldProjectGroupIDs, newProjectGroupIDs := data.GetChange(resourceKeyEnvironmentProjectGroups)
 
-	if data.HasChange(resourceKeyEnvironmentDescription) {
-		environment.Description = data.Get(resourceKeyEnvironmentDescription).(string)
+		err := updateEnvironmentProjectGroups(provider.(*providerState), data.Get(resourceKeySpaceID).(string), id,
+			oldProjectGroupIDs.(*schema.Set),
+			newProjectGroupIDs.(*schema.Set),
+		)
+		if err != nil {
+			return err
+		}
 	}
 
-	_, err = client.UpdateEnvironment(environment)
-
-	return err
+	return resourceEnvironmentRead(data, provider)
 }
 
 // Delete an environment resource.
@@ -133,7 +227,10 @@ func resourceEnvironmentDelete(data *schema.ResourceData, provider interface{})
 
 	log.Printf("Delete Environment '%s' (name = '%s').", id, name)
 
-	client := provider.(*octopus.Client)
+	client, err := provider.(*providerState).ClientForResource(data)
+	if err != nil {
+		return err
+	}
 
 	return client.DeleteEnvironment(id)
 }
@@ -144,7 +241,11 @@ func resourceEnvironmentExists(data *schema.ResourceData, provider interface{})
 
 	log.Printf("Check if environment '%s' exists.", id)
 
-	client := provider.(*octopus.Client)
+	var client *octopus.Client
+	client, err = provider.(*providerState).ClientForResource(data)
+	if err != nil {
+		return
+	}
 
 	var environment *octopus.Environment
 	environment, err = client.GetEnvironment(id)
@@ -152,3 +253,67 @@ func resourceEnvironmentExists(data *schema.ResourceData, provider interface{})
 
 	return
 }
+
+// Get the Ids of the project groups associated with an environment.
+func getEnvironmentProjectGroups(client *octopus.Client, environmentID string) (projectGroupIDs []string, err error) {
+	projectGroups, err := getAllProjectGroups(client)
+	if err != nil {
+		return
+	}
+
+	for _, projectGroup := range projectGroups {
+		for _, projectGroupEnvironmentID := range projectGroup.EnvironmentIDs {
+			if projectGroupEnvironmentID == environmentID {
+				projectGroupIDs = append(projectGroupIDs, projectGroup.ID)
+
+				break
+			}
+		}
+	}
+
+	return
+}
+
+// Add an environment to, and / or remove it from, project groups.
+func updateEnvironmentProjectGroups(providerState *providerState, spaceID string, environmentID string, oldProjectGroupIDs *schema.Set, newProjectGroupIDs *schema.Set) error {
+	for _, projectGroupID := range newProjectGroupIDs.Difference(oldProjectGroupIDs).List() {
+		err := updateProjectGroupEnvironments(providerState, spaceID, projectGroupID.(string), func(environmentIDs *schema.Set) {
+			environmentIDs.Add(environmentID)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, projectGroupID := range oldProjectGroupIDs.Difference(newProjectGroupIDs).List() {
+		err := updateProjectGroupEnvironments(providerState, spaceID, projectGroupID.(string), func(environmentIDs *schema.Set) {
+			environmentIDs.Remove(environmentID)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Modify the Ids of environments associated with a project group.
+func updateProjectGroupEnvironments(providerState *providerState, spaceID string, projectGroupID string, update func(environmentIDs *schema.Set)) error {
+	projectGroup, err := providerState.UpdateProjectGroup(spaceID, projectGroupID, func(projectGroup *octopus.ProjectGroup) error {
+		environmentIDs := stringListToSet(projectGroup.EnvironmentIDs)
+		update(environmentIDs)
+		projectGroup.EnvironmentIDs = stringSetToList(environmentIDs)
+
+		log.Printf("Project group '%s' is now associated with environments %#v.", projectGroupID, projectGroup.EnvironmentIDs)
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if projectGroup == nil {
+		return fmt.Errorf("Cannot find project group '%s'.", projectGroupID)
+	}
+
+	return nil
+}
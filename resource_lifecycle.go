@@ -0,0 +1,341 @@
+package main
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"log"
+	"octopus"
+)
+
+const (
+	resourceKeyLifecycleName                     = "name"
+	resourceKeyLifecycleDescription              = "description"
+	resourceKeyLifecycleReleaseRetentionPolicy   = "release_retention_policy"
+	resourceKeyLifecycleTentacleRetentionPolicy  = "tentacle_retention_policy"
+	resourceKeyLifecyclePhase                    = "phase"
+	resourceKeyLifecyclePhaseID                  = "id"
+	resourceKeyLifecyclePhaseName                = "name"
+	resourceKeyLifecyclePhaseAutomaticTargets    = "automatic_deployment_targets"
+	resourceKeyLifecyclePhaseOptionalTargets     = "optional_deployment_targets"
+	resourceKeyLifecyclePhaseMinimumEnvironments = "minimum_environments_before_promotion"
+	resourceKeyLifecyclePhaseIsOptional          = "is_optional_phase"
+)
+
+func resourceLifecycle() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceLifecycleCreate,
+		Read:   resourceLifecycleRead,
+		Update: resourceLifecycleUpdate,
+		Delete: resourceLifecycleDelete,
+		Exists: resourceLifecycleExists,
+		Importer: &schema.ResourceImporter{
+			State: importStateInSpace,
+		},
+
+		Schema: map[string]*schema.Schema{
+			resourceKeySpaceID: schemaSpaceID(true),
+			resourceKeyLifecycleName: &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The lifecycle name.",
+			},
+			resourceKeyLifecycleDescription: &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+				Description: "The lifecycle description.",
+			},
+			resourceKeyLifecycleReleaseRetentionPolicy:  schemaRetentionPolicy("How long releases are kept (if not specified, releases are kept forever)."),
+			resourceKeyLifecycleTentacleRetentionPolicy: schemaRetentionPolicy("How long extracted packages and files are kept on deployment targets (if not specified, they are kept forever)."),
+			resourceKeyLifecyclePhase: &schema.Schema{
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "The lifecycle's phases, in the order that releases progress through them (if none are specified, releases can be deployed to any environment).",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						resourceKeyLifecyclePhaseID: &schema.Schema{
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The phase Id.",
+						},
+						resourceKeyLifecyclePhaseName: &schema.Schema{
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The phase name.",
+						},
+						resourceKeyLifecyclePhaseAutomaticTargets: &schema.Schema{
+							Type: schema.TypeSet,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+							Set:         schema.HashString,
+							Optional:    true,
+							Description: "The Ids of environments that releases are automatically deployed to when they enter the phase.",
+						},
+						resourceKeyLifecyclePhaseOptionalTargets: &schema.Schema{
+							Type: schema.TypeSet,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+							Set:         schema.HashString,
+							Optional:    true,
+							Description: "The Ids of environments that releases can be manually deployed to when they enter the phase.",
+						},
+						resourceKeyLifecyclePhaseMinimumEnvironments: &schema.Schema{
+							Type:        schema.TypeInt,
+							Optional:    true,
+							Default:     0,
+							Description: "The number of the phase's environments that a release must be deployed to before it can progress to the next phase (0 means all of them).",
+						},
+						resourceKeyLifecyclePhaseIsOptional: &schema.Schema{
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "Can releases skip this phase?",
+						},
+						resourceKeyLifecycleReleaseRetentionPolicy:  schemaRetentionPolicy("How long releases in this phase are kept (if not specified, the lifecycle's policy applies)."),
+						resourceKeyLifecycleTentacleRetentionPolicy: schemaRetentionPolicy("How long extracted packages and files for releases in this phase are kept on deployment targets (if not specified, the lifecycle's policy applies)."),
+					},
+				},
+			},
+		},
+	}
+}
+
+// Create a lifecycle resource.
+func resourceLifecycleCreate(data *schema.ResourceData, provider interface{}) error {
+	lifecycle := &octopus.Lifecycle{}
+	err := expandLifecycle(data, lifecycle)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Create lifecycle named '%s' with %d phases.", lifecycle.Name, len(lifecycle.Phases))
+
+	client, err := provider.(*providerState).ClientForResource(data)
+	if err != nil {
+		return err
+	}
+
+	lifecycle, err = client.CreateLifecycle(lifecycle)
+	if err != nil {
+		return err
+	}
+
+	data.SetId(lifecycle.ID)
+
+	return resourceLifecycleRead(data, provider)
+}
+
+// Read a lifecycle resource.
+func resourceLifecycleRead(data *schema.ResourceData, provider interface{}) error {
+	id := data.Id()
+	name := data.Get(resourceKeyLifecycleName).(string)
+
+	log.Printf("Read lifecycle '%s' (name = '%s').", id, name)
+
+	client, err := provider.(*providerState).ClientForResource(data)
+	if err != nil {
+		return err
+	}
+
+	lifecycle, err := client.GetLifecycle(id)
+	if err != nil {
+		return err
+	}
+	if lifecycle == nil {
+		// Lifecycle has been deleted.
+		data.SetId("")
+
+		return nil
+	}
+
+	data.Set(resourceKeyLifecycleName, lifecycle.Name)
+	data.Set(resourceKeyLifecycleDescription, lifecycle.Description)
+	data.Set(resourceKeyLifecycleReleaseRetentionPolicy, flattenLifecycleRetentionPolicy(data, resourceKeyLifecycleReleaseRetentionPolicy, lifecycle.ReleaseRetentionPolicy))
+	data.Set(resourceKeyLifecycleTentacleRetentionPolicy, flattenLifecycleRetentionPolicy(data, resourceKeyLifecycleTentacleRetentionPolicy, lifecycle.TentacleRetentionPolicy))
+	data.Set(resourceKeyLifecyclePhase, flattenLifecyclePhases(lifecycle.Phases))
+
+	return nil
+}
+
+// Update a lifecycle resource.
+func resourceLifecycleUpdate(data *schema.ResourceData, provider interface{}) error {
+	id := data.Id()
+
+	log.Printf("Update lifecycle '%s'.", id)
+
+	client, err := provider.(*providerState).ClientForResource(data)
+	if err != nil {
+		return err
+	}
+
+	lifecycle, err := client.GetLifecycle(id)
+	if err != nil {
+		return err
+	}
+	if lifecycle == nil {
+		// Lifecycle has been deleted.
+		data.SetId("")
+
+		return nil
+	}
+
+	err = expandLifecycle(data, lifecycle)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.UpdateLifecycle(lifecycle)
+	if err != nil {
+		return err
+	}
+
+	return resourceLifecycleRead(data, provider)
+}
+
+// Delete a lifecycle resource.
+func resourceLifecycleDelete(data *schema.ResourceData, provider interface{}) error {
+	id := data.Id()
+	name := data.Get(resourceKeyLifecycleName).(string)
+
+	log.Printf("Delete lifecycle '%s' (name = '%s').", id, name)
+
+	client, err := provider.(*providerState).ClientForResource(data)
+	if err != nil {
+		return err
+	}
+
+	return client.DeleteLifecycle(id)
+}
+
+// Determine whether a lifecycle resource exists.
+func resourceLifecycleExists(data *schema.ResourceData, provider interface{}) (exists bool, err error) {
+	id := data.Id()
+
+	log.Printf("Check if lifecycle '%s' exists.", id)
+
+	var client *octopus.Client
+	client, err = provider.(*providerState).ClientForResource(data)
+	if err != nil {
+		return
+	}
+
+	var lifecycle *octopus.Lifecycle
+	lifecycle, err = client.GetLifecycle(id)
+	exists = lifecycle != nil
+
+	return
+}
+
+// Apply the lifecycle settings from the resource configuration to a lifecycle.
+//
+// Octopus requires lifecycle-level retention policies, so a retention policy that is not configured keeps everything forever.
+func expandLifecycle(data *schema.ResourceData, lifecycle *octopus.Lifecycle) error {
+	lifecycle.Name = data.Get(resourceKeyLifecycleName).(string)
+	lifecycle.Description = data.Get(resourceKeyLifecycleDescription).(string)
+
+	releaseRetentionPolicy, err := expandRetentionPolicy(data.Get(resourceKeyLifecycleReleaseRetentionPolicy))
+	if err != nil {
+		return err
+	}
+	if releaseRetentionPolicy == nil {
+		releaseRetentionPolicy = newKeepForeverRetentionPolicy()
+	}
+	lifecycle.ReleaseRetentionPolicy = releaseRetentionPolicy
+
+	tentacleRetentionPolicy, err := expandRetentionPolicy(data.Get(resourceKeyLifecycleTentacleRetentionPolicy))
+	if err != nil {
+		return err
+	}
+	if tentacleRetentionPolicy == nil {
+		tentacleRetentionPolicy = newKeepForeverRetentionPolicy()
+	}
+	lifecycle.TentacleRetentionPolicy = tentacleRetentionPolicy
+
+	phases, err := expandLifecyclePhases(data.Get(resourceKeyLifecyclePhase).([]interface{}), lifecycle.Phases)
+	if err != nil {
+		return err
+	}
+	lifecycle.Phases = phases
+
+	return nil
+}
+
+// Convert a lifecycle-level Octopus retention period to a retention policy block's properties.
+//
+// A policy that keeps everything forever is equivalent to not specifying one, so it is only included if the resource already has a retention policy block (otherwise it would show a difference on every plan).
+func flattenLifecycleRetentionPolicy(data *schema.ResourceData, key string, retentionPolicy *octopus.RetentionPeriod) []interface{} {
+	if retentionPolicy != nil && retentionPolicy.ShouldKeepForever && len(data.Get(key).([]interface{})) == 0 {
+		return []interface{}{}
+	}
+
+	return flattenRetentionPolicy(retentionPolicy)
+}
+
+// Convert "phase" blocks to Octopus lifecycle phases.
+//
+// Existing phases are matched on name so that their Ids are preserved.
+func expandLifecyclePhases(phaseBlocks []interface{}, existingPhases []octopus.LifecyclePhase) ([]octopus.LifecyclePhase, error) {
+	existingPhaseIDs := make(map[string]string, len(existingPhases))
+	for _, existingPhase := range existingPhases {
+		existingPhaseIDs[existingPhase.Name] = existingPhase.ID
+	}
+
+	phases := make([]octopus.LifecyclePhase, len(phaseBlocks))
+	phaseNames := make(map[string]bool, len(phaseBlocks))
+	for index, phaseBlock := range phaseBlocks {
+		properties := phaseBlock.(map[string]interface{})
+
+		phase := octopus.LifecyclePhase{
+			Name:                               properties[resourceKeyLifecyclePhaseName].(string),
+			AutomaticDeploymentTargets:         stringSetToList(properties[resourceKeyLifecyclePhaseAutomaticTargets].(*schema.Set)),
+			OptionalDeploymentTargets:          stringSetToList(properties[resourceKeyLifecyclePhaseOptionalTargets].(*schema.Set)),
+			MinimumEnvironmentsBeforePromotion: properties[resourceKeyLifecyclePhaseMinimumEnvironments].(int),
+			IsOptionalPhase:                    properties[resourceKeyLifecyclePhaseIsOptional].(bool),
+		}
+		if phaseNames[phase.Name] {
+			return nil, fmt.Errorf("Lifecycle has more than one phase named '%s'.", phase.Name)
+		}
+		phaseNames[phase.Name] = true
+		for _, environmentID := range phase.AutomaticDeploymentTargets {
+			if containsString(phase.OptionalDeploymentTargets, environmentID) {
+				return nil, fmt.Errorf("Environment '%s' cannot be both an automatic and an optional deployment target of lifecycle phase '%s'.", environmentID, phase.Name)
+			}
+		}
+		phase.ID = existingPhaseIDs[phase.Name]
+
+		var err error
+		phase.ReleaseRetentionPolicy, err = expandRetentionPolicy(properties[resourceKeyLifecycleReleaseRetentionPolicy])
+		if err != nil {
+			return nil, err
+		}
+		phase.TentacleRetentionPolicy, err = expandRetentionPolicy(properties[resourceKeyLifecycleTentacleRetentionPolicy])
+		if err != nil {
+			return nil, err
+		}
+
+		phases[index] = phase
+	}
+
+	return phases, nil
+}
+
+// Convert Octopus lifecycle phases to "phase" blocks.
+func flattenLifecyclePhases(phases []octopus.LifecyclePhase) []interface{} {
+	phaseBlocks := make([]interface{}, len(phases))
+	for index, phase := range phases {
+		phaseBlocks[index] = map[string]interface{}{
+			resourceKeyLifecyclePhaseID:                  phase.ID,
+			resourceKeyLifecyclePhaseName:                phase.Name,
+			resourceKeyLifecyclePhaseAutomaticTargets:    stringListToSet(phase.AutomaticDeploymentTargets),
+			resourceKeyLifecyclePhaseOptionalTargets:     stringListToSet(phase.OptionalDeploymentTargets),
+			resourceKeyLifecyclePhaseMinimumEnvironments: phase.MinimumEnvironmentsBeforePromotion,
+			resourceKeyLifecyclePhaseIsOptional:          phase.IsOptionalPhase,
+			resourceKeyLifecycleReleaseRetentionPolicy:   flattenRetentionPolicy(phase.ReleaseRetentionPolicy),
+			resourceKeyLifecycleTentacleRetentionPolicy:  flattenRetentionPolicy(phase.TentacleRetentionPolicy),
+		}
+	}
+
+	return phaseBlocks
+}
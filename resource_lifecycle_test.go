@@ -0,0 +1,90 @@
+package main
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+	"octopus"
+	"testing"
+)
+
+func TestExpandLifecycleKeepsForeverWhenRetentionPolicyIsRemoved(t *testing.T) {
+	data := schema.TestResourceDataRaw(t, resourceLifecycle().Schema, map[string]interface{}{
+		resourceKeyLifecycleName: "Default",
+	})
+
+	// The lifecycle's existing retention policies (configured by a previous apply).
+	lifecycle := &octopus.Lifecycle{
+		ReleaseRetentionPolicy: &octopus.RetentionPeriod{
+			Unit:           retentionUnitDays,
+			QuantityToKeep: 30,
+		},
+		TentacleRetentionPolicy: &octopus.RetentionPeriod{
+			Unit:           retentionUnitItems,
+			QuantityToKeep: 3,
+		},
+	}
+
+	err := expandLifecycle(data, lifecycle)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, retentionPolicy := range map[string]*octopus.RetentionPeriod{
+		resourceKeyLifecycleReleaseRetentionPolicy:  lifecycle.ReleaseRetentionPolicy,
+		resourceKeyLifecycleTentacleRetentionPolicy: lifecycle.TentacleRetentionPolicy,
+	} {
+		if retentionPolicy == nil || !retentionPolicy.ShouldKeepForever || retentionPolicy.QuantityToKeep != 0 {
+			t.Errorf("Expected %s to keep everything forever, but found %#v.", name, retentionPolicy)
+		}
+	}
+}
+
+func TestExpandLifecycleAppliesConfiguredRetentionPolicy(t *testing.T) {
+	data := schema.TestResourceDataRaw(t, resourceLifecycle().Schema, map[string]interface{}{
+		resourceKeyLifecycleName: "Default",
+		resourceKeyLifecycleReleaseRetentionPolicy: []interface{}{
+			map[string]interface{}{
+				resourceKeyRetentionPolicyUnit:           retentionUnitItems,
+				resourceKeyRetentionPolicyQuantityToKeep: 5,
+			},
+		},
+	})
+
+	lifecycle := &octopus.Lifecycle{}
+	err := expandLifecycle(data, lifecycle)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	retentionPolicy := lifecycle.ReleaseRetentionPolicy
+	if retentionPolicy == nil || retentionPolicy.ShouldKeepForever || retentionPolicy.Unit != retentionUnitItems || retentionPolicy.QuantityToKeep != 5 {
+		t.Fatalf("Unexpected release retention policy: %#v.", retentionPolicy)
+	}
+	if lifecycle.TentacleRetentionPolicy == nil || !lifecycle.TentacleRetentionPolicy.ShouldKeepForever {
+		t.Fatalf("Expected the tentacle retention policy to keep everything forever, but found %#v.", lifecycle.TentacleRetentionPolicy)
+	}
+}
+
+func TestFlattenLifecycleRetentionPolicyOmitsKeepForeverUnlessConfigured(t *testing.T) {
+	keepForever := newKeepForeverRetentionPolicy()
+
+	data := schema.TestResourceDataRaw(t, resourceLifecycle().Schema, map[string]interface{}{
+		resourceKeyLifecycleName: "Default",
+	})
+	flattened := flattenLifecycleRetentionPolicy(data, resourceKeyLifecycleReleaseRetentionPolicy, keepForever)
+	if len(flattened) != 0 {
+		t.Fatalf("Expected no retention policy block, but found %#v.", flattened)
+	}
+
+	data = schema.TestResourceDataRaw(t, resourceLifecycle().Schema, map[string]interface{}{
+		resourceKeyLifecycleName: "Default",
+		resourceKeyLifecycleReleaseRetentionPolicy: []interface{}{
+			map[string]interface{}{
+				resourceKeyRetentionPolicyKeepForever: true,
+			},
+		},
+	})
+	flattened = flattenLifecycleRetentionPolicy(data, resourceKeyLifecycleReleaseRetentionPolicy, keepForever)
+	if len(flattened) != 1 {
+		t.Fatalf("Expected a retention policy block, but found %#v.", flattened)
+	}
+}
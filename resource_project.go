@@ -0,0 +1,382 @@
+package main
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"log"
+	"octopus"
+)
+
+const (
+	resourceKeyProjectName                            = "name"
+	resourceKeyProjectDescription                     = "description"
+	resourceKeyProjectProjectGroupID                  = "project_group"
+	resourceKeyProjectLifecycleID                     = "lifecycle_id"
+	resourceKeyProjectIsDisabled                      = "is_disabled"
+	resourceKeyProjectAutoCreateRelease               = "auto_create_release"
+	resourceKeyProjectDefaultToSkipIfAlreadyInstalled = "default_to_skip_if_already_installed"
+	resourceKeyProjectDefaultGuidedFailureMode        = "default_guided_failure_mode"
+	resourceKeyProjectTenantedDeploymentMode          = "tenanted_deployment_mode"
+	resourceKeyProjectLibraryVariableSets             = "library_variable_sets"
+	resourceKeyProjectReleaseVersionTemplate          = "release_version_template"
+	resourceKeyProjectDiscreteChannelRelease          = "discrete_channel_release"
+	resourceKeyProjectDeploymentProcessID             = "deployment_process"
+	resourceKeyProjectReleaseCreationAction           = "release_creation_action"
+	resourceKeyProjectReleaseCreationPackage          = "release_creation_package"
+	resourceKeyProjectReleaseCreationChannelID        = "release_creation_channel"
+)
+
+const (
+	guidedFailureModeEnvironmentDefault = "EnvironmentDefault"
+	guidedFailureModeOff                = "Off"
+	guidedFailureModeOn                 = "On"
+)
+
+const (
+	tenantedDeploymentModeUntenanted           = "Untenanted"
+	tenantedDeploymentModeTenantedOrUntenanted = "TenantedOrUntenanted"
+	tenantedDeploymentModeTenanted             = "Tenanted"
+)
+
+// The default template used to generate release versions.
+
+func resourceProject() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceProjectCreate,
+		Read:   resourceProjectRead,
+		Update: resourceProjectUpdate,
+		Delete: resourceProjectDelete,
+		Exists: resourceProjectExists,
+		Importer: &schema.ResourceImporter{
+			State: importStateInSpace,
+		},
+		CustomizeDiff: resourceProjectCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			resourceKeySpaceID: schemaSpaceID(true),
+			resourceKeyProjectName: &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The project name.",
+			},
+			resourceKeyProjectDescription: &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+				Description: "The project description.",
+			},
+			resourceKeyProjectProjectGroupID: &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The Id of the project group that contains the project.",
+			},
+			resourceKeyProjectLifecycleID: &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The Id of the project's default lifecycle.",
+			},
+			resourceKeyProjectIsDisabled: &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Is the project disabled?",
+			},
+			resourceKeyProjectAutoCreateRelease: &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Automatically create a release when a package is pushed to the built-in package repository (if true, release_creation_action must also be specified)?",
+			},
+			resourceKeyProjectReleaseCreationAction: &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The name of the deployment action whose package triggers automatic release creation.",
+			},
+			resourceKeyProjectReleaseCreationPackage: &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The name of the package reference (of release_creation_action) that triggers automatic release creation (if not specified, the action's primary package is used).",
+			},
+			resourceKeyProjectReleaseCreationChannelID: &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The Id of the channel that automatically-created releases are created in (if not specified, the project's default channel is used).",
+			},
+			resourceKeyProjectDefaultToSkipIfAlreadyInstalled: &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "By default, skip deploying packages that are already installed?",
+			},
+			resourceKeyProjectDefaultGuidedFailureMode: &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      guidedFailureModeEnvironmentDefault,
+				ValidateFunc: validateGuidedFailureMode,
+				Description:  "The default guided failure mode for the project's deployments (EnvironmentDefault, Off, or On).",
+			},
+			resourceKeyProjectTenantedDeploymentMode: &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      tenantedDeploymentModeUntenanted,
+				ValidateFunc: validateTenantedDeploymentMode,
+				Description:  "Can the project be deployed to tenants (Untenanted, TenantedOrUntenanted, or Tenanted)?",
+			},
+			resourceKeyProjectLibraryVariableSets: &schema.Schema{
+				Type: schema.TypeSet,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Set:         schema.HashString,
+				Optional:    true,
+				Description: "The Ids of library variable sets included in the project.",
+			},
+			resourceKeyProjectReleaseVersionTemplate: &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The template used to generate the version numbers of the project's releases (if not specified, the template is left unchanged; Octopus's default for new projects is '#{Octopus.Version.LastMajor}.#{Octopus.Version.LastMinor}.#{Octopus.Version.NextPatch}').",
+			},
+			resourceKeyProjectDiscreteChannelRelease: &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Treat releases of different channels as distinct (i.e. show each channel's latest release on the dashboard)?",
+			},
+			resourceKeyProjectDeploymentProcessID: &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The Id of the project's deployment process.",
+			},
+		},
+	}
+}
+
+// Create a project resource.
+func resourceProjectCreate(data *schema.ResourceData, provider interface{}) error {
+	project := &octopus.Project{}
+	expandProject(data, project)
+
+	log.Printf("Create project named '%s' in project group '%s'.", project.Name, project.ProjectGroupID)
+
+	client, err := provider.(*providerState).ClientForResource(data)
+	if err != nil {
+		return err
+	}
+
+	project, err = client.CreateProject(project)
+	if err != nil {
+		return err
+	}
+
+	data.SetId(project.ID)
+
+	return resourceProjectRead(data, provider)
+}
+
+// Read a project resource.
+func resourceProjectRead(data *schema.ResourceData, provider interface{}) error {
+	id := data.Id()
+	name := data.Get(resourceKeyProjectName).(string)
+
+	log.Printf("Read project '%s' (name = '%s').", id, name)
+
+	client, err := provider.(*providerState).ClientForResource(data)
+	if err != nil {
+		return err
+	}
+
+	project, err := client.GetProject(id)
+	if err != nil {
+		return err
+	}
+	if project == nil {
+		// Project has been deleted.
+		data.SetId("")
+
+		return nil
+	}
+
+	data.Set(resourceKeyProjectName, project.Name)
+	data.Set(resourceKeyProjectDescription, project.Description)
+	data.Set(resourceKeyProjectProjectGroupID, project.ProjectGroupID)
+	data.Set(resourceKeyProjectLifecycleID, project.LifecycleID)
+	data.Set(resourceKeyProjectIsDisabled, project.IsDisabled)
+	data.Set(resourceKeyProjectAutoCreateRelease, project.AutoCreateRelease)
+	data.Set(resourceKeyProjectDefaultToSkipIfAlreadyInstalled, project.DefaultToSkipIfAlreadyInstalled)
+	data.Set(resourceKeyProjectDefaultGuidedFailureMode, project.DefaultGuidedFailureMode)
+	data.Set(resourceKeyProjectTenantedDeploymentMode, project.TenantedDeploymentMode)
+	data.Set(resourceKeyProjectLibraryVariableSets, stringListToSet(project.IncludedLibraryVariableSetIDs))
+	data.Set(resourceKeyProjectDiscreteChannelRelease, project.DiscreteChannelRelease)
+	data.Set(resourceKeyProjectDeploymentProcessID, project.DeploymentProcessID)
+
+	if project.VersioningStrategy != nil {
+		data.Set(resourceKeyProjectReleaseVersionTemplate, project.VersioningStrategy.Template)
+	}
+
+	releaseCreationAction := ""
+	releaseCreationPackage := ""
+	releaseCreationChannelID := ""
+	if project.ReleaseCreationStrategy != nil {
+		if project.ReleaseCreationStrategy.ReleaseCreationPackage != nil {
+			releaseCreationAction = project.ReleaseCreationStrategy.ReleaseCreationPackage.DeploymentAction
+			releaseCreationPackage = project.ReleaseCreationStrategy.ReleaseCreationPackage.PackageReference
+		}
+		releaseCreationChannelID = project.ReleaseCreationStrategy.ChannelID
+	}
+	data.Set(resourceKeyProjectReleaseCreationAction, releaseCreationAction)
+	data.Set(resourceKeyProjectReleaseCreationPackage, releaseCreationPackage)
+	data.Set(resourceKeyProjectReleaseCreationChannelID, releaseCreationChannelID)
+
+	return nil
+}
+
+// Update a project resource.
+func resourceProjectUpdate(data *schema.ResourceData, provider interface{}) error {
+	id := data.Id()
+
+	log.Printf("Update project '%s'.", id)
+
+	client, err := provider.(*providerState).ClientForResource(data)
+	if err != nil {
+		return err
+	}
+
+	project, err := client.GetProject(id)
+	if err != nil {
+		return err
+	}
+	if project == nil {
+		// Project has been deleted.
+		data.SetId("")
+
+		return nil
+	}
+
+	expandProject(data, project)
+
+	_, err = client.UpdateProject(project)
+	if err != nil {
+		return err
+	}
+
+	return resourceProjectRead(data, provider)
+}
+
+// Delete a project resource.
+func resourceProjectDelete(data *schema.ResourceData, provider interface{}) error {
+	id := data.Id()
+	name := data.Get(resourceKeyProjectName).(string)
+
+	log.Printf("Delete project '%s' (name = '%s').", id, name)
+
+	client, err := provider.(*providerState).ClientForResource(data)
+	if err != nil {
+		return err
+	}
+
+	return client.DeleteProject(id)
+}
+
+// Determine whether a project resource exists.
+func resourceProjectExists(data *schema.ResourceData, provider interface{}) (exists bool, err error) {
+	id := data.Id()
+
+	log.Printf("Check if project '%s' exists.", id)
+
+	var client *octopus.Client
+	client, err = provider.(*providerState).ClientForResource(data)
+	if err != nil {
+		return
+	}
+
+	var project *octopus.Project
+	project, err = client.GetProject(id)
+	exists = project != nil
+
+	return
+}
+
+// Apply the project settings from the resource configuration to a project.
+//
+// Properties not managed by the resource (e.g. the deployment process Id) are left unchanged.
+func expandProject(data *schema.ResourceData, project *octopus.Project) {
+	project.Name = data.Get(resourceKeyProjectName).(string)
+	project.Description = data.Get(resourceKeyProjectDescription).(string)
+	project.ProjectGroupID = data.Get(resourceKeyProjectProjectGroupID).(string)
+	project.LifecycleID = data.Get(resourceKeyProjectLifecycleID).(string)
+	project.IsDisabled = data.Get(resourceKeyProjectIsDisabled).(bool)
+	project.AutoCreateRelease = data.Get(resourceKeyProjectAutoCreateRelease).(bool)
+	project.DefaultToSkipIfAlreadyInstalled = data.Get(resourceKeyProjectDefaultToSkipIfAlreadyInstalled).(bool)
+	project.DefaultGuidedFailureMode = data.Get(resourceKeyProjectDefaultGuidedFailureMode).(string)
+	project.TenantedDeploymentMode = data.Get(resourceKeyProjectTenantedDeploymentMode).(string)
+	project.IncludedLibraryVariableSetIDs = stringSetToList(data.Get(resourceKeyProjectLibraryVariableSets).(*schema.Set))
+	project.DiscreteChannelRelease = data.Get(resourceKeyProjectDiscreteChannelRelease).(bool)
+
+	releaseVersionTemplate, ok := data.GetOk(resourceKeyProjectReleaseVersionTemplate)
+	if ok {
+		if project.VersioningStrategy == nil {
+			project.VersioningStrategy = &octopus.ProjectVersioningStrategy{}
+		}
+		project.VersioningStrategy.Template = releaseVersionTemplate.(string)
+	}
+
+	releaseCreationAction := data.Get(resourceKeyProjectReleaseCreationAction).(string)
+	releaseCreationChannelID := data.Get(resourceKeyProjectReleaseCreationChannelID).(string)
+	if isEmpty(releaseCreationAction) && isEmpty(releaseCreationChannelID) {
+		project.ReleaseCreationStrategy = nil
+	} else {
+		if project.ReleaseCreationStrategy == nil {
+			project.ReleaseCreationStrategy = &octopus.ReleaseCreationStrategy{}
+		}
+		project.ReleaseCreationStrategy.ReleaseCreationPackage = nil
+		if !isEmpty(releaseCreationAction) {
+			project.ReleaseCreationStrategy.ReleaseCreationPackage = &octopus.DeploymentActionPackage{
+				DeploymentAction: releaseCreationAction,
+				PackageReference: data.Get(resourceKeyProjectReleaseCreationPackage).(string),
+			}
+		}
+		project.ReleaseCreationStrategy.ChannelID = releaseCreationChannelID
+	}
+}
+
+// Validate a project resource's automatic release creation settings when the plan is created (rather than waiting until it is applied).
+func resourceProjectCustomizeDiff(diff *schema.ResourceDiff, provider interface{}) error {
+	if !diff.NewValueKnown(resourceKeyProjectReleaseCreationAction) {
+		return nil // Interpolated from another resource; validated when applied.
+	}
+
+	autoCreateRelease := diff.Get(resourceKeyProjectAutoCreateRelease).(bool)
+	if autoCreateRelease && isEmpty(diff.Get(resourceKeyProjectReleaseCreationAction).(string)) {
+		return fmt.Errorf("'%s' must be specified when '%s' is true.", resourceKeyProjectReleaseCreationAction, resourceKeyProjectAutoCreateRelease)
+	}
+	if !isEmpty(diff.Get(resourceKeyProjectReleaseCreationPackage).(string)) && isEmpty(diff.Get(resourceKeyProjectReleaseCreationAction).(string)) {
+		return fmt.Errorf("'%s' can only be specified if '%s' is also specified.", resourceKeyProjectReleaseCreationPackage, resourceKeyProjectReleaseCreationAction)
+	}
+
+	return nil
+}
+
+// Validate a guided failure mode.
+func validateGuidedFailureMode(value interface{}, key string) (warnings []string, errors []error) {
+	guidedFailureMode := value.(string)
+	switch guidedFailureMode {
+	case guidedFailureModeEnvironmentDefault, guidedFailureModeOff, guidedFailureModeOn:
+	default:
+		errors = append(errors, fmt.Errorf("Unsupported guided failure mode '%s' for property '%s' (expected EnvironmentDefault, Off, or On).", guidedFailureMode, key))
+	}
+
+	return
+}
+
+// Validate a tenanted deployment mode.
+func validateTenantedDeploymentMode(value interface{}, key string) (warnings []string, errors []error) {
+	tenantedDeploymentMode := value.(string)
+	switch tenantedDeploymentMode {
+	case tenantedDeploymentModeUntenanted, tenantedDeploymentModeTenantedOrUntenanted, tenantedDeploymentModeTenanted:
+	default:
+		errors = append(errors, fmt.Errorf("Unsupported tenanted deployment mode '%s' for property '%s' (expected Untenanted, TenantedOrUntenanted, or Tenanted).", tenantedDeploymentMode, key))
+	}
+
+	return
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+	"log"
+	"octopus"
+)
+
+const (
+	resourceKeyProjectGroupName              = "name"
+	resourceKeyProjectGroupDescription       = "description"
+	resourceKeyProjectGroupRetentionPolicyID = "retention_policy"
+	resourceKeyProjectGroupEnvironments      = "environments"
+)
+
+func resourceProjectGroup() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceProjectGroupCreate,
+		Read:   resourceProjectGroupRead,
+		Update: resourceProjectGroupUpdate,
+		Delete: resourceProjectGroupDelete,
+		Exists: resourceProjectGroupExists,
+		Importer: &schema.ResourceImporter{
+			State: importStateInSpace,
+		},
+
+		Schema: map[string]*schema.Schema{
+			resourceKeySpaceID: schemaSpaceID(true),
+			resourceKeyProjectGroupName: &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The project group name.",
+			},
+			resourceKeyProjectGroupDescription: &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+				Description: "The project group description.",
+			},
+			resourceKeyProjectGroupRetentionPolicyID: &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The Id of the project group's retention policy (if not specified, the server's default is used).",
+			},
+			resourceKeyProjectGroupEnvironments: &schema.Schema{
+				Type: schema.TypeSet,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Set:         schema.HashString,
+				Computed:    true,
+				Description: "The Ids of environments associated with the project group (use the project_groups property of octopus_environment to manage these associations).",
+			},
+		},
+	}
+}
+
+// Create a project group resource.
+func resourceProjectGroupCreate(data *schema.ResourceData, provider interface{}) error {
+	projectGroup := &octopus.ProjectGroup{
+		Name:              data.Get(resourceKeyProjectGroupName).(string),
+		Description:       data.Get(resourceKeyProjectGroupDescription).(string),
+		RetentionPolicyID: data.Get(resourceKeyProjectGroupRetentionPolicyID).(string),
+	}
+
+	log.Printf("Create project group named '%s'.", projectGroup.Name)
+
+	client, err := provider.(*providerState).ClientForResource(data)
+	if err != nil {
+		return err
+	}
+
+	projectGroup, err = client.CreateProjectGroup(projectGroup)
+	if err != nil {
+		return err
+	}
+
+	data.SetId(projectGroup.ID)
+
+	return resourceProjectGroupRead(data, provider)
+}
+
+// Read a project group resource.
+func resourceProjectGroupRead(data *schema.ResourceData, provider interface{}) error {
+	id := data.Id()
+	name := data.Get(resourceKeyProjectGroupName).(string)
+
+	log.Printf("Read project group '%s' (name = '%s').", id, name)
+
+	client, err := provider.(*providerState).ClientForResource(data)
+	if err != nil {
+		return err
+	}
+
+	projectGroup, err := client.GetProjectGroup(id)
+	if err != nil {
+		return err
+	}
+	if projectGroup == nil {
+		// Project group has been deleted.
+		data.SetId("")
+
+		return nil
+	}
+
+	data.Set(resourceKeyProjectGroupName, projectGroup.Name)
+	data.Set(resourceKeyProjectGroupDescription, projectGroup.Description)
+	data.Set(resourceKeyProjectGroupRetentionPolicyID, projectGroup.RetentionPolicyID)
+	data.Set(resourceKeyProjectGroupEnvironments, stringListToSet(projectGroup.EnvironmentIDs))
+
+	return nil
+}
+
+// Update a project group resource.
+//
+// The project group's environments are managed by octopus_environment, so they are left unchanged.
+func resourceProjectGroupUpdate(data *schema.ResourceData, provider interface{}) error {
+	id := data.Id()
+
+	log.Printf("Update project group '%s'.", id)
+
+	// Environment resources may be modifying the same project group concurrently.
+	projectGroup, err := provider.(*providerState).UpdateProjectGroup(data.Get(resourceKeySpaceID).(string), id, func(projectGroup *octopus.ProjectGroup) error {
+		projectGroup.Name = data.Get(resourceKeyProjectGroupName).(string)
+		projectGroup.Description = data.Get(resourceKeyProjectGroupDescription).(string)
+		if data.HasChange(resourceKeyProjectGroupRetentionPolicyID) {
+			projectGroup.RetentionPolicyID = data.Get(resourceKeyProjectGroupRetentionPolicyID).(string)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if projectGroup == nil {
+		// Project group has been deleted.
+		data.SetId("")
+
+		return nil
+	}
+
+	return resourceProjectGroupRead(data, provider)
+}
+
+// Delete a project group resource.
+func resourceProjectGroupDelete(data *schema.ResourceData, provider interface{}) error {
+	id := data.Id()
+	name := data.Get(resourceKeyProjectGroupName).(string)
+
+	log.Printf("Delete project group '%s' (name = '%s').", id, name)
+
+	client, err := provider.(*providerState).ClientForResource(data)
+	if err != nil {
+		return err
+	}
+
+	return client.DeleteProjectGroup(id)
+}
+
+// Determine whether a project group resource exists.
+func resourceProjectGroupExists(data *schema.ResourceData, provider interface{}) (exists bool, err error) {
+	id := data.Id()
+
+	log.Printf("Check if project group '%s' exists.", id)
+
+	var client *octopus.Client
+	client, err = provider.(*providerState).ClientForResource(data)
+	if err != nil {
+		return
+	}
+
+	var projectGroup *octopus.ProjectGroup
+	projectGroup, err = client.GetProjectGroup(id)
+	exists = projectGroup != nil
+
+	return
+}
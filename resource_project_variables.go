@@ -0,0 +1,345 @@
+package main
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"log"
+	"octopus"
+)
+
+const (
+	resourceKeyProjectVariablesProjectID            = resourceKeyVariableProjectID
+	resourceKeyProjectVariablesLibraryVariableSetID = resourceKeyVariableLibraryVariableSetID
+	resourceKeyProjectVariablesVariable             = "variable"
+	resourceKeyProjectVariablesVariableID           = "id"
+)
+
+func resourceProjectVariables() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceProjectVariablesCreate,
+		Read:   resourceProjectVariablesRead,
+		Update: resourceProjectVariablesUpdate,
+		Delete: resourceProjectVariablesDelete,
+
+		Schema: map[string]*schema.Schema{
+			resourceKeySpaceID: schemaSpaceID(true),
+			resourceKeyProjectVariablesProjectID: &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{resourceKeyProjectVariablesLibraryVariableSetID},
+				Description:   "The Id of the project whose variables are managed by this resource.",
+			},
+			resourceKeyProjectVariablesLibraryVariableSetID: &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{resourceKeyProjectVariablesProjectID},
+				Description:   "The Id of the library variable set whose variables are managed by this resource.",
+			},
+			resourceKeyProjectVariablesVariable: &schema.Schema{
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "The variables in the variable set (any variables not declared here will be removed).",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						resourceKeyProjectVariablesVariableID: &schema.Schema{
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The variable Id.",
+						},
+						resourceKeyVariableName: &schema.Schema{
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The variable name.",
+						},
+						resourceKeyVariableValue: &schema.Schema{
+							Type:        schema.TypeString,
+							Optional:    true,
+							Default:     "",
+							Sensitive:   true,
+							Description: "The variable value.",
+						},
+						resourceKeyVariableIsSensitive: &schema.Schema{
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "Is the variable sensitive?",
+						},
+						resourceKeyVariableType: &schema.Schema{
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      variableTypeString,
+							ValidateFunc: validateProjectVariablesType,
+							Description:  "The variable type (String, Certificate, AzureAccount, AmazonWebServicesAccount, GoogleCloudAccount, or WorkerPool).",
+						},
+						resourceKeyVariableEnvironments: schemaProjectVariablesScope("The Ids of environments to which the variable is scoped."),
+						resourceKeyVariableRoles:        schemaProjectVariablesScope("The roles to which the variable is scoped."),
+						resourceKeyVariableMachines:     schemaProjectVariablesScope("The Ids of machines to which the variable is scoped."),
+						resourceKeyVariableActions:      schemaProjectVariablesScope("The Ids of actions to which the variable is scoped."),
+						resourceKeyVariableChannels:     schemaProjectVariablesScope("The Ids of channels to which the variable is scoped."),
+						resourceKeyVariableTenantTags:   schemaProjectVariablesScope("The canonical names of tenant tags to which the variable is scoped."),
+						resourceKeyVariableProcesses:    schemaProjectVariablesScope("The Ids of processes to which the variable is scoped."),
+						resourceKeyVariablePrompt:       schemaVariablePrompt(),
+					},
+				},
+			},
+		},
+	}
+}
+
+// The schema for one of a declared variable's scope dimensions.
+func schemaProjectVariablesScope(description string) *schema.Schema {
+	return &schema.Schema{
+		Type: schema.TypeSet,
+		Elem: &schema.Schema{
+			Type: schema.TypeString,
+		},
+		Set:         schema.HashString,
+		Optional:    true,
+		Description: description,
+	}
+}
+
+// Create a project variables resource.
+func resourceProjectVariablesCreate(data *schema.ResourceData, provider interface{}) error {
+	owner, err := getVariableOwner(data)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Create variables for %s.", owner)
+
+	variableSet, err := applyProjectVariables(data, provider.(*providerState), owner)
+	if err != nil {
+		return err
+	}
+
+	data.SetId(variableSet.ID)
+
+	return readProjectVariables(data, variableSet)
+}
+
+// Read a project variables resource.
+func resourceProjectVariablesRead(data *schema.ResourceData, provider interface{}) error {
+	id := data.Id()
+	owner, err := getVariableOwner(data)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Read variables '%s' for %s.", id, owner)
+
+	client, err := provider.(*providerState).ClientForResource(data)
+	if err != nil {
+		return err
+	}
+
+	variableSet, err := owner.GetVariableSet(client)
+	if err != nil {
+		return err
+	}
+	if variableSet == nil {
+		// Owner has been deleted.
+		data.SetId("")
+
+		return nil
+	}
+
+	return readProjectVariables(data, variableSet)
+}
+
+// Update a project variables resource.
+func resourceProjectVariablesUpdate(data *schema.ResourceData, provider interface{}) error {
+	id := data.Id()
+	owner, err := getVariableOwner(data)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Update variables '%s' for %s.", id, owner)
+
+	if !data.HasChange(resourceKeyProjectVariablesVariable) {
+		return nil // Nothing to do.
+	}
+
+	variableSet, err := applyProjectVariables(data, provider.(*providerState), owner)
+	if err != nil {
+		return err
+	}
+
+	return readProjectVariables(data, variableSet)
+}
+
+// Delete a project variables resource.
+func resourceProjectVariablesDelete(data *schema.ResourceData, provider interface{}) error {
+	id := data.Id()
+	owner, err := getVariableOwner(data)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Delete variables '%s' for %s.", id, owner)
+
+	providerState := provider.(*providerState)
+
+	_, err = providerState.UpdateVariableSet(owner, func(variableSet *octopus.VariableSet) (modified bool, err error) {
+		if len(variableSet.Variables) == 0 {
+			return false, nil
+		}
+		variableSet.Variables = []octopus.Variable{}
+
+		return true, nil
+	})
+	if err != nil {
+		if _, ok := err.(*variableSetNotFoundError); ok {
+			log.Printf("Variable set for %s not found; treating its variables as already deleted.", owner)
+
+			return nil
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// Replace the variables in the owner's variable set with the variables declared in the resource configuration.
+//
+// Existing variables are matched on name and scopes (just like octopus_variable) so that their Ids are preserved.
+func applyProjectVariables(data *schema.ResourceData, providerState *providerState, owner variableOwner) (*octopus.VariableSet, error) {
+	declaredVariables, err := expandProjectVariables(data.Get(resourceKeyProjectVariablesVariable).(*schema.Set))
+	if err != nil {
+		return nil, err
+	}
+
+	return providerState.UpdateVariableSet(owner, func(variableSet *octopus.VariableSet) (modified bool, err error) {
+		variables := make([]octopus.Variable, len(declaredVariables))
+		for index, declaredVariable := range declaredVariables {
+			matchingVariables := findVariablesByNameAndScopes(variableSet, declaredVariable.Name, declaredVariable.Scope)
+			if len(matchingVariables) > 1 {
+				return false, fmt.Errorf("Multiple variables in %s exactly match scope %#v for variable '%s'.", owner, declaredVariable.Scope, declaredVariable.Name)
+			}
+			if len(matchingVariables) == 1 {
+				declaredVariable.ID = matchingVariables[0].ID
+			}
+
+			variables[index] = declaredVariable
+		}
+
+		log.Printf("Variable set for %s will contain %d variable(s) (previously %d).", owner, len(variables), len(variableSet.Variables))
+		variableSet.Variables = variables
+
+		return true, nil
+	})
+}
+
+// Update the resource's state from the owner's variable set.
+func readProjectVariables(data *schema.ResourceData, variableSet *octopus.VariableSet) error {
+	// Octopus never returns the values of sensitive variables, so we keep the values from configuration / state.
+	priorVariables, err := expandProjectVariables(data.Get(resourceKeyProjectVariablesVariable).(*schema.Set))
+	if err != nil {
+		return err
+	}
+	priorVariableSet := &octopus.VariableSet{
+		Variables: priorVariables,
+	}
+
+	variables := make([]interface{}, len(variableSet.Variables))
+	for index, variable := range variableSet.Variables {
+		value := variable.Value
+		if variable.IsSensitive {
+			value = ""
+			matchingVariables := findVariablesByNameAndScopes(priorVariableSet, variable.Name, variable.Scope)
+			if len(matchingVariables) == 1 {
+				value = matchingVariables[0].Value
+			}
+		}
+
+		variableType := variable.Type
+		if variableType == variableTypeSensitive || isEmpty(variableType) {
+			variableType = variableTypeString
+		}
+
+		variables[index] = map[string]interface{}{
+			resourceKeyProjectVariablesVariableID: variable.ID,
+			resourceKeyVariableName:               variable.Name,
+			resourceKeyVariableValue:              value,
+			resourceKeyVariableIsSensitive:        variable.IsSensitive,
+			resourceKeyVariableType:               variableType,
+			resourceKeyVariableEnvironments:       stringListToSet(variable.Scope.Environments),
+			resourceKeyVariableRoles:              stringListToSet(variable.Scope.Roles),
+			resourceKeyVariableMachines:           stringListToSet(variable.Scope.Machines),
+			resourceKeyVariableActions:            stringListToSet(variable.Scope.Actions),
+			resourceKeyVariableChannels:           stringListToSet(variable.Scope.Channels),
+			resourceKeyVariableTenantTags:         stringListToSet(variable.Scope.TenantTags),
+			resourceKeyVariableProcesses:          stringListToSet(variable.Scope.ProcessOwners),
+			resourceKeyVariablePrompt:             flattenVariablePrompt(variable.Prompt),
+		}
+	}
+
+	return data.Set(resourceKeyProjectVariablesVariable, variables)
+}
+
+// Convert the declared "variable" blocks to Octopus variables.
+func expandProjectVariables(declaredVariables *schema.Set) ([]octopus.Variable, error) {
+	variables := make([]octopus.Variable, 0, declaredVariables.Len())
+	for _, declaredVariable := range declaredVariables.List() {
+		properties := declaredVariable.(map[string]interface{})
+
+		variable := octopus.Variable{
+			ID:    properties[resourceKeyProjectVariablesVariableID].(string),
+			Name:  properties[resourceKeyVariableName].(string),
+			Value: properties[resourceKeyVariableValue].(string),
+			Type:  properties[resourceKeyVariableType].(string),
+			Scope: octopus.VariableScopes{
+				Environments:  stringSetToList(properties[resourceKeyVariableEnvironments].(*schema.Set)),
+				Roles:         stringSetToList(properties[resourceKeyVariableRoles].(*schema.Set)),
+				Machines:      stringSetToList(properties[resourceKeyVariableMachines].(*schema.Set)),
+				Actions:       stringSetToList(properties[resourceKeyVariableActions].(*schema.Set)),
+				Channels:      stringSetToList(properties[resourceKeyVariableChannels].(*schema.Set)),
+				TenantTags:    stringSetToList(properties[resourceKeyVariableTenantTags].(*schema.Set)),
+				ProcessOwners: stringSetToList(properties[resourceKeyVariableProcesses].(*schema.Set)),
+			},
+		}
+
+		if properties[resourceKeyVariableIsSensitive].(bool) {
+			if variable.Type != variableTypeString {
+				return nil, fmt.Errorf("Variable '%s' cannot be sensitive because it has type '%s'.", variable.Name, variable.Type)
+			}
+
+			variable.Type = variableTypeSensitive
+			variable.IsSensitive = true
+		}
+
+		err := validateVariableValue(variable.Type, variable.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		variable.Prompt, err = expandVariablePrompt(properties[resourceKeyVariablePrompt])
+		if err != nil {
+			return nil, err
+		}
+
+		existingVariables := findVariablesByNameAndScopes(&octopus.VariableSet{Variables: variables}, variable.Name, variable.Scope)
+		if len(existingVariables) != 0 {
+			return nil, fmt.Errorf("Variable '%s' is declared more than once with scope %#v.", variable.Name, variable.Scope)
+		}
+
+		variables = append(variables, variable)
+	}
+
+	return variables, nil
+}
+
+// Validate the "type" property of a declared variable (sensitive variables are declared using "is_sensitive").
+func validateProjectVariablesType(value interface{}, key string) (warnings []string, errors []error) {
+	if value.(string) == variableTypeSensitive {
+		errors = append(errors, fmt.Errorf("Use 'is_sensitive' rather than type '%s' for property '%s'.", variableTypeSensitive, key))
+
+		return
+	}
+
+	return validateVariableType(value, key)
+}
@@ -0,0 +1,265 @@
+package main
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"log"
+	"octopus"
+)
+
+const (
+	resourceKeySpaceName                = "name"
+	resourceKeySpaceDescription         = "description"
+	resourceKeySpaceManagersTeams       = "space_managers_teams"
+	resourceKeySpaceManagersTeamMembers = "space_managers_team_members"
+	resourceKeySpaceTaskQueueStopped    = "task_queue_stopped"
+	resourceKeySpaceIsDefault           = "is_default"
+)
+
+func resourceSpace() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceSpaceCreate,
+		Read:   resourceSpaceRead,
+		Update: resourceSpaceUpdate,
+		Delete: resourceSpaceDelete,
+		Exists: resourceSpaceExists,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			resourceKeySpaceName: &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The space name.",
+			},
+			resourceKeySpaceDescription: &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+				Description: "The space description.",
+			},
+			resourceKeySpaceManagersTeams: &schema.Schema{
+				Type: schema.TypeSet,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Set:         schema.HashString,
+				Optional:    true,
+				Description: "The Ids of teams whose members manage the space (the space's built-in 'Space Managers' team, which Octopus adds automatically, is not included).",
+			},
+			resourceKeySpaceManagersTeamMembers: &schema.Schema{
+				Type: schema.TypeSet,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Set:         schema.HashString,
+				Optional:    true,
+				Description: "The Ids of users who manage the space.",
+			},
+			resourceKeySpaceTaskQueueStopped: &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Is the space's task queue stopped (i.e. no new tasks will be started in the space)?",
+			},
+			resourceKeySpaceIsDefault: &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Computed:    true,
+				Description: "Is this the default space (if not specified, the space's existing setting is left unchanged, so the default space can be imported without specifying it)?",
+			},
+		},
+	}
+}
+
+// Create a space resource.
+func resourceSpaceCreate(data *schema.ResourceData, provider interface{}) error {
+	space := &octopus.Space{}
+	err := expandSpace(data, space)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Create space named '%s'.", space.Name)
+
+	client, err := provider.(*providerState).ServerClient()
+	if err != nil {
+		return err
+	}
+
+	space, err = client.CreateSpace(space)
+	if err != nil {
+		return err
+	}
+
+	data.SetId(space.ID)
+
+	return resourceSpaceRead(data, provider)
+}
+
+// Read a space resource.
+func resourceSpaceRead(data *schema.ResourceData, provider interface{}) error {
+	id := data.Id()
+	name := data.Get(resourceKeySpaceName).(string)
+
+	log.Printf("Read space '%s' (name = '%s').", id, name)
+
+	client, err := provider.(*providerState).ServerClient()
+	if err != nil {
+		return err
+	}
+
+	space, err := client.GetSpace(id)
+	if err != nil {
+		return err
+	}
+	if space == nil {
+		// Space has been deleted.
+		data.SetId("")
+
+		return nil
+	}
+
+	data.Set(resourceKeySpaceName, space.Name)
+	data.Set(resourceKeySpaceDescription, space.Description)
+	data.Set(resourceKeySpaceManagersTeams, stringListToSet(withoutBuiltInSpaceManagersTeam(space.ID, space.SpaceManagersTeams)))
+	data.Set(resourceKeySpaceManagersTeamMembers, stringListToSet(space.SpaceManagersTeamMembers))
+	data.Set(resourceKeySpaceTaskQueueStopped, space.TaskQueueStopped)
+	data.Set(resourceKeySpaceIsDefault, space.IsDefault)
+
+	return nil
+}
+
+// Update a space resource.
+func resourceSpaceUpdate(data *schema.ResourceData, provider interface{}) error {
+	id := data.Id()
+
+	log.Printf("Update space '%s'.", id)
+
+	client, err := provider.(*providerState).ServerClient()
+	if err != nil {
+		return err
+	}
+
+	space, err := client.GetSpace(id)
+	if err != nil {
+		return err
+	}
+	if space == nil {
+		// Space has been deleted.
+		data.SetId("")
+
+		return nil
+	}
+
+	err = expandSpace(data, space)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.UpdateSpace(space)
+	if err != nil {
+		return err
+	}
+
+	return resourceSpaceRead(data, provider)
+}
+
+// Delete a space resource.
+//
+// Octopus will not delete a space whose task queue is still running, so the task queue is stopped first.
+func resourceSpaceDelete(data *schema.ResourceData, provider interface{}) error {
+	id := data.Id()
+	name := data.Get(resourceKeySpaceName).(string)
+
+	log.Printf("Delete space '%s' (name = '%s').", id, name)
+
+	client, err := provider.(*providerState).ServerClient()
+	if err != nil {
+		return err
+	}
+
+	space, err := client.GetSpace(id)
+	if err != nil {
+		return err
+	}
+	if space == nil {
+		log.Printf("Space '%s' not found; treating it as already deleted.", id)
+
+		return nil
+	}
+
+	if !space.TaskQueueStopped {
+		log.Printf("Stop task queue for space '%s' before deleting it.", id)
+
+		space.TaskQueueStopped = true
+		_, err = client.UpdateSpace(space)
+		if err != nil {
+			return fmt.Errorf("Failed to stop the task queue for space '%s': %s", id, err.Error())
+		}
+	}
+
+	return client.DeleteSpace(id)
+}
+
+// Determine whether a space resource exists.
+func resourceSpaceExists(data *schema.ResourceData, provider interface{}) (exists bool, err error) {
+	id := data.Id()
+
+	log.Printf("Check if space '%s' exists.", id)
+
+	var client *octopus.Client
+	client, err = provider.(*providerState).ServerClient()
+	if err != nil {
+		return
+	}
+
+	var space *octopus.Space
+	space, err = client.GetSpace(id)
+	exists = space != nil
+
+	return
+}
+
+// Apply the space properties from the resource configuration to a space.
+func expandSpace(data *schema.ResourceData, space *octopus.Space) error {
+	space.Name = data.Get(resourceKeySpaceName).(string)
+	space.Description = data.Get(resourceKeySpaceDescription).(string)
+	builtInTeamID := builtInSpaceManagersTeamID(space.ID)
+	hasBuiltInTeam := containsString(space.SpaceManagersTeams, builtInTeamID)
+	space.SpaceManagersTeams = stringSetToList(data.Get(resourceKeySpaceManagersTeams).(*schema.Set))
+	if hasBuiltInTeam && !containsString(space.SpaceManagersTeams, builtInTeamID) {
+		space.SpaceManagersTeams = append(space.SpaceManagersTeams, builtInTeamID)
+	}
+	space.SpaceManagersTeamMembers = stringSetToList(data.Get(resourceKeySpaceManagersTeamMembers).(*schema.Set))
+	space.TaskQueueStopped = data.Get(resourceKeySpaceTaskQueueStopped).(bool)
+	space.IsDefault = data.Get(resourceKeySpaceIsDefault).(bool)
+
+	if len(space.SpaceManagersTeams) == 0 && len(space.SpaceManagersTeamMembers) == 0 {
+		return fmt.Errorf("Space '%s' must have at least one space manager (specify '%s' and / or '%s').", space.Name, resourceKeySpaceManagersTeams, resourceKeySpaceManagersTeamMembers)
+	}
+
+	return nil
+}
+
+// Get the Id of the built-in "Space Managers" team that Octopus automatically adds to a space's managers.
+func builtInSpaceManagersTeamID(spaceID string) string {
+	return "teams-spacemanagers-" + spaceID
+}
+
+// Remove a space's built-in "Space Managers" team from a list of team Ids.
+//
+// Octopus adds this team to every space, so it would otherwise always differ from the configured teams.
+func withoutBuiltInSpaceManagersTeam(spaceID string, teamIDs []string) []string {
+	builtInTeamID := builtInSpaceManagersTeamID(spaceID)
+
+	filteredTeamIDs := make([]string, 0, len(teamIDs))
+	for _, teamID := range teamIDs {
+		if teamID != builtInTeamID {
+			filteredTeamIDs = append(filteredTeamIDs, teamID)
+		}
+	}
+
+	return filteredTeamIDs
+}
@@ -5,41 +5,97 @@ import (
 	"github.com/hashicorp/terraform/helper/schema"
 	"log"
 	"octopus"
+	"strings"
 )
 
 const (
-	resourceKeyVariableProjectID    = "project"
-	resourceKeyVariableName         = "name"
-	resourceKeyVariableValue        = "value"
-	resourceKeyVariableEnvironments = "environments"
-	resourceKeyVariableRoles        = "roles"
-	resourceKeyVariableMachines     = "machines"
-	resourceKeyVariableActions      = "actions"
+	resourceKeyVariableProjectID            = "project"
+	resourceKeyVariableLibraryVariableSetID = "library_variable_set"
+	resourceKeyVariableName                 = "name"
+	resourceKeyVariableValue                = "value"
+	resourceKeyVariableEnvironments         = "environments"
+	resourceKeyVariableRoles                = "roles"
+	resourceKeyVariableMachines             = "machines"
+	resourceKeyVariableActions              = "actions"
+	resourceKeyVariableChannels             = "channels"
+	resourceKeyVariableTenantTags           = "tenant_tags"
+	resourceKeyVariableProcesses            = "processes"
+	resourceKeyVariableIsSensitive          = "is_sensitive"
+	resourceKeyVariableType                 = "type"
+	resourceKeyVariablePrompt               = "prompt"
 )
 
+const (
+	variableTypeString                   = "String"
+	variableTypeSensitive                = "Sensitive"
+	variableTypeCertificate              = "Certificate"
+	variableTypeAzureAccount             = "AzureAccount"
+	variableTypeAmazonWebServicesAccount = "AmazonWebServicesAccount"
+	variableTypeGoogleCloudAccount       = "GoogleCloudAccount"
+	variableTypeWorkerPool               = "WorkerPool"
+)
+
+// The Id prefixes expected for the values of variable types that reference other Octopus entities.
+var variableTypeValuePrefixes = map[string]string{
+	variableTypeCertificate:              "Certificates-",
+	variableTypeAzureAccount:             "Accounts-",
+	variableTypeAmazonWebServicesAccount: "Accounts-",
+	variableTypeGoogleCloudAccount:       "Accounts-",
+	variableTypeWorkerPool:               "WorkerPools-",
+}
+
 func resourceVariable() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceVariableCreate,
 		Read:   resourceVariableRead,
 		Update: resourceVariableUpdate,
 		Delete: resourceVariableDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceVariableImport,
+		},
+		CustomizeDiff: resourceVariableCustomizeDiff,
 
 		Schema: map[string]*schema.Schema{
+			resourceKeySpaceID: schemaSpaceID(true),
 			resourceKeyVariableProjectID: &schema.Schema{
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "The variable name.",
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{resourceKeyVariableLibraryVariableSetID},
+				Description:   "The Id of the project that owns the variable.",
+			},
+			resourceKeyVariableLibraryVariableSetID: &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{resourceKeyVariableProjectID},
+				Description:   "The Id of the library variable set that owns the variable.",
 			},
 			resourceKeyVariableName: &schema.Schema{
 				Type:     schema.TypeString,
 				Required: true,
 			},
 			resourceKeyVariableValue: &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				Computed: true,
-				Default:  nil,
+				Type:      schema.TypeString,
+				Optional:  true,
+				Computed:  true,
+				Default:   nil,
+				Sensitive: true,
+			},
+			resourceKeyVariableIsSensitive: &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Computed:    true,
+				Description: "Is the variable sensitive? Octopus never returns the values of sensitive variables, so the value in configuration is assumed to be current.",
 			},
+			resourceKeyVariableType: &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validateVariableType,
+				Description:  "The variable type (String, Sensitive, Certificate, AzureAccount, AmazonWebServicesAccount, GoogleCloudAccount, or WorkerPool).",
+			},
+			resourceKeyVariablePrompt: schemaVariablePrompt(),
 			resourceKeyVariableEnvironments: &schema.Schema{
 				Type: schema.TypeList,
 				Elem: &schema.Schema{
@@ -72,70 +128,108 @@ func resourceVariable() *schema.Resource {
 				Optional: true,
 				Computed: true,
 			},
+			resourceKeyVariableChannels: &schema.Schema{
+				Type: schema.TypeList,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Optional:    true,
+				Computed:    true,
+				Description: "The Ids of channels to which the variable is scoped.",
+			},
+			resourceKeyVariableTenantTags: &schema.Schema{
+				Type: schema.TypeList,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Optional:    true,
+				Computed:    true,
+				Description: "The canonical names of tenant tags (e.g. 'Tier/Gold') to which the variable is scoped.",
+			},
+			resourceKeyVariableProcesses: &schema.Schema{
+				Type: schema.TypeList,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Optional:    true,
+				Computed:    true,
+				Description: "The Ids of processes (deployment process or runbooks) to which the variable is scoped.",
+			},
 		},
 	}
 }
 
 // Create a variable resource.
 func resourceVariableCreate(data *schema.ResourceData, provider interface{}) error {
-	propertyHelper := propertyHelper(data)
-
-	projectID := data.Get(resourceKeyVariableProjectID).(string)
+	owner, err := getVariableOwner(data)
+	if err != nil {
+		return err
+	}
 	name := data.Get(resourceKeyVariableName).(string)
+	value := data.Get(resourceKeyVariableValue).(string)
 
-	targetScope := octopus.VariableScopes{
-		Environments: propertyHelper.GetStringList(resourceKeyVariableEnvironments),
-		Roles:        propertyHelper.GetStringList(resourceKeyVariableRoles),
-		Machines:     propertyHelper.GetStringList(resourceKeyVariableMachines),
-		Actions:      propertyHelper.GetStringList(resourceKeyVariableActions),
+	variableType, err := getVariableType(data)
+	if err != nil {
+		return err
 	}
-
-	log.Printf("Create variable '%s' for project '%s' (must match scopes: %#v)...", name, projectID, targetScope)
-
-	providerClient := provider.(*octopus.Client)
-
-	variableSet, err := providerClient.GetProjectVariableSet(projectID)
+	err = validateVariableValue(variableType, value)
 	if err != nil {
-		return fmt.Errorf("Error retrieving variable set for project '%s': %s", projectID, err.Error())
+		return err
 	}
-	if variableSet == nil {
-		return fmt.Errorf("Cannot find variable set for project '%s'", projectID)
+
+	prompt, err := expandVariablePrompt(data.Get(resourceKeyVariablePrompt))
+	if err != nil {
+		return err
 	}
 
-	matchingVariables := variableSet.GetVariablesByNameAndScopes(name, targetScope)
+	targetScope := getVariableScopes(data)
+
+	log.Printf("Create variable '%s' for %s (must match scopes: %#v)...", name, owner, targetScope)
 
-	var variable octopus.Variable
-	if len(matchingVariables) == 0 {
-		log.Printf("Create variable '%s' for project '%s' with scope %#v...", name, projectID, targetScope)
+	providerState := provider.(*providerState)
+
+	variableSet, err := providerState.UpdateVariableSet(owner, func(variableSet *octopus.VariableSet) (modified bool, err error) {
+		matchingVariables := findVariablesByNameAndScopes(variableSet, name, targetScope)
+		if len(matchingVariables) == 1 {
+			log.Printf("Variable '%s' already exists for %s with scope %#v.", name, owner, targetScope)
+
+			return false, nil
+		} else if len(matchingVariables) > 1 {
+			return false, fmt.Errorf("Multiple variables exactly match scope %#v for variable '%s'.", targetScope, name)
+		}
+
+		log.Printf("Create variable '%s' for %s with scope %#v...", name, owner, targetScope)
 
 		variableSet.Variables = append(variableSet.Variables, octopus.Variable{
-			Name:  name,
-			Scope: targetScope,
+			Name:        name,
+			Value:       value,
+			Type:        variableType,
+			IsSensitive: variableType == variableTypeSensitive,
+			Scope:       targetScope,
+			Prompt:      prompt,
 		})
 
-		variableSet, err = providerClient.UpdateVariableSet(variableSet)
-		if err != nil {
-			return err
-		}
+		return true, nil
+	})
+	if err != nil {
+		return err
+	}
 
-		matchingVariables = variableSet.GetVariablesByNameAndScopes(name, targetScope)
-		if len(matchingVariables) != 1 {
-			return fmt.Errorf("Found %d matching variables named '%s' for scope %#v (after attempting to create this variable for that scope).", len(matchingVariables), name, targetScope)
-		}
-	} else if len(matchingVariables) == 1 {
-		log.Printf("Variable '%s' already exists for project '%s' with scope %#v.", name, projectID, targetScope)
-	} else {
-		return fmt.Errorf("Multiple variables exactly match scope %#v for variable '%s'.", name, targetScope)
+	matchingVariables := findVariablesByNameAndScopes(variableSet, name, targetScope)
+	if len(matchingVariables) != 1 {
+		return fmt.Errorf("Found %d matching variables named '%s' for scope %#v (after attempting to create this variable for that scope).", len(matchingVariables), name, targetScope)
 	}
 
-	variable = matchingVariables[0]
+	variable := matchingVariables[0]
 	data.SetId(variable.ID)
-	data.Set(resourceKeyVariableValue, variable.Value)
+	data.Set(resourceKeyVariableType, variable.Type)
+	data.Set(resourceKeyVariablePrompt, flattenVariablePrompt(variable.Prompt))
+	data.Set(resourceKeyVariableIsSensitive, variable.IsSensitive)
+	if !variable.IsSensitive {
+		data.Set(resourceKeyVariableValue, variable.Value)
+	}
 
-	propertyHelper.SetStringList(resourceKeyVariableEnvironments, variable.Scope.Environments)
-	propertyHelper.SetStringList(resourceKeyVariableRoles, variable.Scope.Roles)
-	propertyHelper.SetStringList(resourceKeyVariableMachines, variable.Scope.Machines)
-	propertyHelper.SetStringList(resourceKeyVariableActions, variable.Scope.Actions)
+	setVariableScopes(data, variable.Scope)
 
 	return nil
 }
@@ -143,18 +237,24 @@ func resourceVariableCreate(data *schema.ResourceData, provider interface{}) err
 // Read a variable resource.
 func resourceVariableRead(data *schema.ResourceData, provider interface{}) error {
 	id := data.Id()
-	projectID := data.Get(resourceKeyVariableProjectID).(string)
+	owner, err := getVariableOwner(data)
+	if err != nil {
+		return err
+	}
 
-	log.Printf("Read variable '%s' (for project '%s').", id, projectID)
+	log.Printf("Read variable '%s' (for %s).", id, owner)
 
-	providerClient := provider.(*octopus.Client)
+	providerClient, err := provider.(*providerState).ClientForResource(data)
+	if err != nil {
+		return err
+	}
 
-	variableSet, err := providerClient.GetProjectVariableSet(projectID)
+	variableSet, err := owner.GetVariableSet(providerClient)
 	if err != nil {
 		return err
 	}
 	if variableSet == nil {
-		return fmt.Errorf("Cannot find variable set for project '%s'.", projectID)
+		return fmt.Errorf("Cannot find variable set for %s.", owner)
 	}
 
 	variable := variableSet.GetVariableByID(id)
@@ -165,14 +265,17 @@ func resourceVariableRead(data *schema.ResourceData, provider interface{}) error
 		return nil
 	}
 
-	data.Set(resourceKeyVariableValue, variable.Value)
+	data.Set(resourceKeyVariableName, variable.Name)
+	data.Set(resourceKeyVariableType, variable.Type)
+	data.Set(resourceKeyVariablePrompt, flattenVariablePrompt(variable.Prompt))
+	data.Set(resourceKeyVariableIsSensitive, variable.IsSensitive)
+	if !variable.IsSensitive {
+		// Octopus never returns the values of sensitive variables.
+		data.Set(resourceKeyVariableValue, variable.Value)
+	}
 
 	log.Printf("Variable scope is now %#v", variable.Scope)
-	propertyHelper := propertyHelper(data)
-	propertyHelper.SetStringList(resourceKeyVariableEnvironments, variable.Scope.Environments)
-	propertyHelper.SetStringList(resourceKeyVariableRoles, variable.Scope.Roles)
-	propertyHelper.SetStringList(resourceKeyVariableMachines, variable.Scope.Machines)
-	propertyHelper.SetStringList(resourceKeyVariableActions, variable.Scope.Actions)
+	setVariableScopes(data, variable.Scope)
 
 	return nil
 }
@@ -180,71 +283,212 @@ func resourceVariableRead(data *schema.ResourceData, provider interface{}) error
 // Update a variable resource.
 func resourceVariableUpdate(data *schema.ResourceData, provider interface{}) error {
 	id := data.Id()
-	projectID := data.Get(resourceKeyVariableProjectID).(string)
+	owner, err := getVariableOwner(data)
+	if err != nil {
+		return err
+	}
+	name := data.Get(resourceKeyVariableName).(string)
+
+	log.Printf("Update variable '%s' (for %s).", id, owner)
 
-	log.Printf("Update variable '%s' (for project '%s').", id, projectID)
+	scopeChanged := hasVariableScopeChange(data)
 
-	providerClient := provider.(*octopus.Client)
+	newScope := getVariableScopes(data)
 
-	variableSet, err := providerClient.GetProjectVariableSet(projectID)
+	variableType, err := getVariableType(data)
 	if err != nil {
 		return err
 	}
-	if variableSet == nil {
-		return fmt.Errorf("Cannot find variable set for project '%s'.", projectID)
+	err = validateVariableValue(variableType, data.Get(resourceKeyVariableValue).(string))
+	if err != nil {
+		return err
 	}
 
-	variable := variableSet.GetVariableByID(id)
-	if variable == nil {
+	prompt, err := expandVariablePrompt(data.Get(resourceKeyVariablePrompt))
+	if err != nil {
+		return err
+	}
+
+	providerState := provider.(*providerState)
+
+	variableDeleted := false
+	variableSet, err := providerState.UpdateVariableSet(owner, func(variableSet *octopus.VariableSet) (modified bool, err error) {
+		variable := variableSet.GetVariableByID(id)
+		if variable == nil {
+			variableDeleted = true
+
+			return false, nil
+		}
+
+		targetScope := variable.Scope
+		if scopeChanged {
+			targetScope = newScope
+		}
+
+		if scopeChanged || data.HasChange(resourceKeyVariableName) {
+			log.Printf("Variable '%s' (for %s) will now be named '%s' with scope %#v.", id, owner, name, targetScope)
+
+			for _, matchingVariable := range findVariablesByNameAndScopes(variableSet, name, targetScope) {
+				if matchingVariable.ID != id {
+					return false, fmt.Errorf("Cannot update variable '%s' in %s: variable '%s' already exists with name '%s' and scope %#v.", id, owner, matchingVariable.ID, name, targetScope)
+				}
+			}
+		}
+
+		variableSet.UpdateVariable(id, func(variable *octopus.Variable) {
+			if data.HasChange(resourceKeyVariableValue) || variable.Type != variableType {
+				// Octopus does not return the values of sensitive variables, so we always send the value from configuration when sensitivity changes.
+				value, ok := data.GetOk(resourceKeyVariableValue)
+				if ok {
+					variable.Value = value.(string)
+				}
+			}
+
+			variable.Type = variableType
+			variable.IsSensitive = variableType == variableTypeSensitive
+			variable.Prompt = prompt
+
+			variable.Name = name
+			variable.Scope = targetScope
+		})
+
+		return true, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if variableDeleted {
 		// Variable has been deleted.
 		data.SetId("")
 
 		return nil
 	}
 
-	variableSet.UpdateVariable(id, func(variable *octopus.Variable) {
-		if data.HasChange(resourceKeyVariableValue) {
-			value, ok := data.GetOk(resourceKeyVariableValue)
-			if ok {
-				variable.Value = value.(string)
-			} else {
-				data.Set(resourceKeyVariableValue, variable.Value)
-			}
-		}
+	variable := variableSet.GetVariableByID(id)
+	if variable == nil {
+		return fmt.Errorf("Variable '%s' is missing from the variable set for %s after it was updated.", id, owner)
+	}
 
-		// TODO: Propagate scope changes (if any).
-	})
+	data.Set(resourceKeyVariableType, variable.Type)
+	data.Set(resourceKeyVariablePrompt, flattenVariablePrompt(variable.Prompt))
+	data.Set(resourceKeyVariableIsSensitive, variable.IsSensitive)
+	if !variable.IsSensitive {
+		data.Set(resourceKeyVariableValue, variable.Value)
+	}
 
-	_, err = providerClient.UpdateVariableSet(variableSet)
+	setVariableScopes(data, variable.Scope)
 
-	return err
+	return nil
 }
 
 // Delete a variable resource.
 func resourceVariableDelete(data *schema.ResourceData, provider interface{}) error {
 	id := data.Id()
-	projectID := data.Get(resourceKeyVariableProjectID).(string)
+	owner, err := getVariableOwner(data)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Delete variable '%s' (for %s).", id, owner)
+
+	providerState := provider.(*providerState)
+
+	_, err = providerState.UpdateVariableSet(owner, func(variableSet *octopus.VariableSet) (modified bool, err error) {
+		remainingVariables := make([]octopus.Variable, 0, len(variableSet.Variables))
+		for _, variable := range variableSet.Variables {
+			if variable.ID != id {
+				remainingVariables = append(remainingVariables, variable)
+			}
+		}
+		if len(remainingVariables) == len(variableSet.Variables) {
+			log.Printf("Variable '%s' not found in %s; treating it as already deleted.", id, owner)
 
-	log.Printf("Delete variable '%s' (for project '%s').", id, projectID)
+			return false, nil
+		}
+		variableSet.Variables = remainingVariables
 
-	providerClient := provider.(*octopus.Client)
-	providerClient.Reset() // TODO: Replace call to Reset with appropriate API call(s).
+		return true, nil
+	})
+	if err != nil {
+		if _, ok := err.(*variableSetNotFoundError); ok {
+			log.Printf("Variable set for %s not found; treating variable '%s' as already deleted.", owner, id)
+
+			return nil
+		}
+
+		return fmt.Errorf("Error removing variable '%s' from variable set for %s: %s", id, owner, err.Error())
+	}
 
 	return nil
 }
 
+// Import a variable resource.
+//
+// The import Id is the Id of the variable's owner (project or library variable set) and the variable Id, separated by a slash (e.g. "Projects-1/5f3e6d1a-...").
+func resourceVariableImport(data *schema.ResourceData, provider interface{}) ([]*schema.ResourceData, error) {
+	importID := data.Id()
+
+	log.Printf("Import variable '%s'.", importID)
+
+	invalidImportID := fmt.Errorf("Invalid import Id '%s' for variable (expected '[space-id/]owner-id/variable-id', e.g. 'Projects-1/variable-id' or 'Spaces-2/Projects-1/variable-id').", importID)
+
+	idSegments := strings.Split(importID, "/")
+	if len(idSegments) == 3 {
+		if isEmpty(idSegments[0]) {
+			return nil, invalidImportID
+		}
+		data.Set(resourceKeySpaceID, idSegments[0])
+		idSegments = idSegments[1:]
+	}
+	if len(idSegments) != 2 {
+		return nil, invalidImportID
+	}
+	ownerID := idSegments[0]
+	variableID := idSegments[1]
+	if isEmpty(ownerID) || isEmpty(variableID) {
+		return nil, invalidImportID
+	}
+
+	if strings.HasPrefix(ownerID, "LibraryVariableSets-") {
+		data.Set(resourceKeyVariableLibraryVariableSetID, ownerID)
+	} else {
+		data.Set(resourceKeyVariableProjectID, ownerID)
+	}
+	data.SetId(variableID)
+
+	err := resourceVariableRead(data, provider)
+	if err != nil {
+		return nil, err
+	}
+	if data.Id() == "" {
+		return nil, fmt.Errorf("Cannot find variable '%s' in the variable set for '%s'.", variableID, ownerID)
+	}
+
+	return []*schema.ResourceData{data}, nil
+}
+
 // Determine whether a variable resource exists.
 func resourceVariableExists(data *schema.ResourceData, provider interface{}) (exists bool, err error) {
 	id := data.Id()
-	projectID := data.Get(resourceKeyVariableProjectID).(string)
+
+	var owner variableOwner
+	owner, err = getVariableOwner(data)
+	if err != nil {
+		return
+	}
 
 	log.Printf("Check if variable '%s' exists.", id)
 
-	client := provider.(*octopus.Client)
+	var client *octopus.Client
+	client, err = provider.(*providerState).ClientForResource(data)
+	if err != nil {
+		return
+	}
 
 	var variableSet *octopus.VariableSet
-	variableSet, err = client.GetProjectVariableSet(projectID)
-	if err != nil {
+	variableSet, err = owner.GetVariableSet(client)
+	if err != nil || variableSet == nil {
 		return
 	}
 
@@ -252,3 +496,74 @@ func resourceVariableExists(data *schema.ResourceData, provider interface{}) (ex
 
 	return
 }
+
+// Determine the Octopus variable type from the resource's "type" and "is_sensitive" properties.
+func getVariableType(data *schema.ResourceData) (string, error) {
+	variableType := data.Get(resourceKeyVariableType).(string)
+	isSensitive := data.Get(resourceKeyVariableIsSensitive).(bool)
+
+	if data.HasChange(resourceKeyVariableIsSensitive) && !data.HasChange(resourceKeyVariableType) {
+		if variableType == variableTypeString || variableType == variableTypeSensitive {
+			variableType = "" // Type follows sensitivity unless explicitly specified.
+		}
+	} else if data.HasChange(resourceKeyVariableType) && !data.HasChange(resourceKeyVariableIsSensitive) {
+		isSensitive = variableType == variableTypeSensitive // Sensitivity follows type.
+	}
+
+	if isEmpty(variableType) {
+		if isSensitive {
+			return variableTypeSensitive, nil
+		}
+
+		return variableTypeString, nil
+	}
+
+	if isSensitive && variableType != variableTypeSensitive {
+		return "", fmt.Errorf("Variable '%s' cannot be sensitive because it has type '%s'.", data.Get(resourceKeyVariableName).(string), variableType)
+	}
+
+	return variableType, nil
+}
+
+// Validate the "type" property of a variable.
+func validateVariableType(value interface{}, key string) (warnings []string, errors []error) {
+	variableType := value.(string)
+	switch variableType {
+	case variableTypeString, variableTypeSensitive:
+		return
+	default:
+		if _, ok := variableTypeValuePrefixes[variableType]; !ok {
+			errors = append(errors, fmt.Errorf("Unsupported variable type '%s' for property '%s'.", variableType, key))
+		}
+	}
+
+	return
+}
+
+// Validate a variable resource's value against its type when the plan is created (rather than waiting until it is applied).
+func resourceVariableCustomizeDiff(diff *schema.ResourceDiff, provider interface{}) error {
+	if !diff.NewValueKnown(resourceKeyVariableType) || !diff.NewValueKnown(resourceKeyVariableValue) {
+		return nil // Interpolated from another resource; validated when applied.
+	}
+
+	return validateVariableValue(
+		diff.Get(resourceKeyVariableType).(string),
+		diff.Get(resourceKeyVariableValue).(string),
+	)
+}
+
+// Validate that a variable's value is appropriate for its type.
+//
+// Variables that reference other Octopus entities (e.g. certificates or accounts) must have the referenced entity's Id as their value.
+func validateVariableValue(variableType string, value string) error {
+	expectedPrefix, ok := variableTypeValuePrefixes[variableType]
+	if !ok || isEmpty(value) {
+		return nil
+	}
+
+	if !strings.HasPrefix(value, expectedPrefix) {
+		return fmt.Errorf("Invalid value '%s' for variable of type '%s' (expected an Id starting with '%s').", value, variableType, expectedPrefix)
+	}
+
+	return nil
+}
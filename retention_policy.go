@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"octopus"
+)
+
+const (
+	resourceKeyRetentionPolicyKeepForever    = "keep_forever"
+	resourceKeyRetentionPolicyUnit           = "unit"
+	resourceKeyRetentionPolicyQuantityToKeep = "quantity_to_keep"
+)
+
+const (
+	retentionUnitDays  = "Days"
+	retentionUnitItems = "Items"
+)
+
+// The schema for a retention policy.
+func schemaRetentionPolicy(description string) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		MaxItems:    1,
+		Description: description,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				resourceKeyRetentionPolicyKeepForever: &schema.Schema{
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Default:     false,
+					Description: "Keep everything forever (if true, unit and quantity_to_keep are ignored)?",
+				},
+				resourceKeyRetentionPolicyUnit: &schema.Schema{
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      retentionUnitDays,
+					ValidateFunc: validateRetentionUnit,
+					Description:  "The unit of quantity_to_keep (Days or Items).",
+				},
+				resourceKeyRetentionPolicyQuantityToKeep: &schema.Schema{
+					Type:        schema.TypeInt,
+					Optional:    true,
+					Default:     0,
+					Description: "The number of days (or items) to keep.",
+				},
+			},
+		},
+	}
+}
+
+// Convert a retention policy block's properties to an Octopus retention period.
+//
+// Returns nil if no retention policy is configured.
+func expandRetentionPolicy(value interface{}) (*octopus.RetentionPeriod, error) {
+	policies := value.([]interface{})
+	if len(policies) == 0 || policies[0] == nil {
+		return nil, nil
+	}
+	properties := policies[0].(map[string]interface{})
+
+	retentionPolicy := &octopus.RetentionPeriod{
+		ShouldKeepForever: properties[resourceKeyRetentionPolicyKeepForever].(bool),
+		Unit:              properties[resourceKeyRetentionPolicyUnit].(string),
+		QuantityToKeep:    properties[resourceKeyRetentionPolicyQuantityToKeep].(int),
+	}
+	if retentionPolicy.ShouldKeepForever {
+		retentionPolicy.QuantityToKeep = 0
+	} else if retentionPolicy.QuantityToKeep < 1 {
+		return nil, fmt.Errorf("A retention policy's %s must be 1 or greater (unless %s is true).", resourceKeyRetentionPolicyQuantityToKeep, resourceKeyRetentionPolicyKeepForever)
+	}
+
+	return retentionPolicy, nil
+}
+
+// Convert an Octopus retention period to a retention policy block's properties.
+func flattenRetentionPolicy(retentionPolicy *octopus.RetentionPeriod) []interface{} {
+	if retentionPolicy == nil {
+		return []interface{}{}
+	}
+
+	return []interface{}{
+		map[string]interface{}{
+			resourceKeyRetentionPolicyKeepForever:    retentionPolicy.ShouldKeepForever,
+			resourceKeyRetentionPolicyUnit:           retentionPolicy.Unit,
+			resourceKeyRetentionPolicyQuantityToKeep: retentionPolicy.QuantityToKeep,
+		},
+	}
+}
+
+// Create a retention policy that keeps everything forever.
+func newKeepForeverRetentionPolicy() *octopus.RetentionPeriod {
+	return &octopus.RetentionPeriod{
+		Unit:              retentionUnitDays,
+		ShouldKeepForever: true,
+	}
+}
+
+// Validate a retention policy unit.
+func validateRetentionUnit(value interface{}, key string) (warnings []string, errors []error) {
+	unit := value.(string)
+	switch unit {
+	case retentionUnitDays, retentionUnitItems:
+	default:
+		errors = append(errors, fmt.Errorf("Unsupported retention policy unit '%s' for property '%s' (expected Days or Items).", unit, key))
+	}
+
+	return
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"log"
+	"octopus"
+	"strings"
+)
+
+const (
+	resourceKeySpaceID = "space_id"
+)
+
+// The schema for the "space_id" property used to override the provider's space for a resource or data-source.
+func schemaSpaceID(forceNew bool) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeString,
+		Optional:    true,
+		Default:     "",
+		ForceNew:    forceNew,
+		Description: "The Id of the space (if not specified, the provider's space is used).",
+	}
+}
+
+// Import a resource that belongs to a space.
+//
+// The import Id is the resource Id, optionally prefixed with the Id of the space (if the resource is not in the provider's space), e.g. "Spaces-2/Environments-1".
+func importStateInSpace(data *schema.ResourceData, provider interface{}) ([]*schema.ResourceData, error) {
+	importID := data.Id()
+
+	log.Printf("Import resource '%s'.", importID)
+
+	invalidImportID := fmt.Errorf("Invalid import Id '%s' (expected '[space-id/]id', e.g. 'Environments-1' or 'Spaces-2/Environments-1').", importID)
+
+	idSegments := strings.Split(importID, "/")
+	if len(idSegments) == 2 {
+		if isEmpty(idSegments[0]) {
+			return nil, invalidImportID
+		}
+		data.Set(resourceKeySpaceID, idSegments[0])
+		idSegments = idSegments[1:]
+	}
+	if len(idSegments) != 1 || isEmpty(idSegments[0]) {
+		return nil, invalidImportID
+	}
+	data.SetId(idSegments[0])
+
+	return []*schema.ResourceData{data}, nil
+}
+
+// Find the Id of the space with the specified name.
+func findSpaceIDByName(client *octopus.Client, name string) (string, error) {
+	spaces, err := client.GetSpaces()
+	if err != nil {
+		return "", err
+	}
+
+	var matchingIDs []string
+	for _, space := range spaces {
+		if space.Name == name {
+			matchingIDs = append(matchingIDs, space.ID)
+		}
+	}
+
+	switch len(matchingIDs) {
+	case 0:
+		return "", fmt.Errorf("Cannot find a space named '%s'.", name)
+	case 1:
+		return matchingIDs[0], nil
+	default:
+		return "", ambiguousNameError("space", name, matchingIDs)
+	}
+}
+
+// Find the Id of the server's default space.
+func findDefaultSpaceID(client *octopus.Client) (string, error) {
+	spaces, err := client.GetSpaces()
+	if err != nil {
+		return "", err
+	}
+
+	for _, space := range spaces {
+		if space.IsDefault {
+			return space.ID, nil
+		}
+	}
+
+	return "", fmt.Errorf("None of the server's spaces is marked as the default space.")
+}
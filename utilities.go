@@ -21,3 +21,49 @@ func stringToPtr(value string) *string {
 func isEmpty(value string) bool {
 	return len(value) == 0
 }
+
+func stringSetToList(set *schema.Set) []string {
+	elements := make([]string, set.Len())
+	for index, element := range set.List() {
+		elements[index] = element.(string)
+	}
+
+	return elements
+}
+
+func stringListToSet(elements []string) *schema.Set {
+	set := newStringSet()
+	for _, element := range elements {
+		set.Add(element)
+	}
+
+	return set
+}
+
+func containsString(elements []string, value string) bool {
+	for _, element := range elements {
+		if element == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func stringMapFromProperties(properties map[string]interface{}) map[string]string {
+	stringMap := make(map[string]string, len(properties))
+	for key, value := range properties {
+		stringMap[key] = value.(string)
+	}
+
+	return stringMap
+}
+
+func stringMapToProperties(stringMap map[string]string) map[string]interface{} {
+	properties := make(map[string]interface{}, len(stringMap))
+	for key, value := range stringMap {
+		properties[key] = value
+	}
+
+	return properties
+}
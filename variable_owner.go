@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"octopus"
+)
+
+// variableOwner identifies the project or library variable set that owns a variable set.
+type variableOwner struct {
+	SpaceID              string
+	ProjectID            string
+	LibraryVariableSetID string
+}
+
+// Get the owner of the variable set for a variable resource or data-source.
+//
+// Exactly one of "project" or "library_variable_set" must be specified.
+func getVariableOwner(data *schema.ResourceData) (owner variableOwner, err error) {
+	owner.SpaceID = data.Get(resourceKeySpaceID).(string)
+	owner.ProjectID = data.Get(resourceKeyVariableProjectID).(string)
+	owner.LibraryVariableSetID = data.Get(resourceKeyVariableLibraryVariableSetID).(string)
+
+	if isEmpty(owner.ProjectID) == isEmpty(owner.LibraryVariableSetID) {
+		err = fmt.Errorf("Exactly one of '%s' or '%s' must be specified for variable '%s'.",
+			resourceKeyVariableProjectID,
+			resourceKeyVariableLibraryVariableSetID,
+			data.Get(resourceKeyVariableName).(string),
+		)
+	}
+
+	return
+}
+
+// IsLibraryVariableSet determines whether the variable set is owned by a library variable set (rather than a project).
+func (owner variableOwner) IsLibraryVariableSet() bool {
+	return !isEmpty(owner.LibraryVariableSetID)
+}
+
+// GetVariableSet retrieves the owner's variable set.
+//
+// Returns nil if the variable set was not found.
+func (owner variableOwner) GetVariableSet(client *octopus.Client) (*octopus.VariableSet, error) {
+	if owner.IsLibraryVariableSet() {
+		return client.GetLibraryVariableSetVariables(owner.LibraryVariableSetID)
+	}
+
+	return client.GetProjectVariableSet(owner.ProjectID)
+}
+
+// String returns a description of the owner (used in log and error messages).
+func (owner variableOwner) String() string {
+	if owner.IsLibraryVariableSet() {
+		return fmt.Sprintf("library variable set '%s'", owner.LibraryVariableSetID)
+	}
+
+	return fmt.Sprintf("project '%s'", owner.ProjectID)
+}
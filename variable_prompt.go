@@ -0,0 +1,189 @@
+package main
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"octopus"
+	"strings"
+)
+
+const (
+	resourceKeyVariablePromptLabel         = "label"
+	resourceKeyVariablePromptHelpText      = "help_text"
+	resourceKeyVariablePromptRequired      = "required"
+	resourceKeyVariablePromptControlType   = "control_type"
+	resourceKeyVariablePromptSelectOptions = "select_option"
+	resourceKeyVariablePromptOptionValue   = "value"
+	resourceKeyVariablePromptOptionDisplay = "display_name"
+)
+
+const (
+	promptControlTypeSingleLineText = "SingleLineText"
+	promptControlTypeMultiLineText  = "MultiLineText"
+	promptControlTypeCheckbox       = "Checkbox"
+	promptControlTypeSelect         = "Select"
+
+	// The display setting that determines a prompted variable's control type.
+	promptDisplaySettingControlType = "Octopus.ControlType"
+
+	// The display setting that lists the options for a "Select" control ("value|display name", one per line).
+	promptDisplaySettingSelectOptions = "Octopus.SelectOptions"
+)
+
+// The schema for a prompted variable's settings.
+func schemaVariablePrompt() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		MaxItems:    1,
+		Description: "Settings for prompting the user for the variable's value when a deployment is created.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				resourceKeyVariablePromptLabel: &schema.Schema{
+					Type:        schema.TypeString,
+					Optional:    true,
+					Default:     "",
+					Description: "The label displayed for the prompt.",
+				},
+				resourceKeyVariablePromptHelpText: &schema.Schema{
+					Type:        schema.TypeString,
+					Optional:    true,
+					Default:     "",
+					Description: "The help text displayed for the prompt.",
+				},
+				resourceKeyVariablePromptRequired: &schema.Schema{
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Default:     false,
+					Description: "Must a value be supplied for the prompt?",
+				},
+				resourceKeyVariablePromptControlType: &schema.Schema{
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      promptControlTypeSingleLineText,
+					ValidateFunc: validatePromptControlType,
+					Description:  "The type of control used to prompt for the value (SingleLineText, MultiLineText, Checkbox, or Select).",
+				},
+				resourceKeyVariablePromptSelectOptions: &schema.Schema{
+					Type:        schema.TypeList,
+					Optional:    true,
+					Description: "The options available when control_type is Select.",
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							resourceKeyVariablePromptOptionValue: &schema.Schema{
+								Type:        schema.TypeString,
+								Required:    true,
+								Description: "The option value.",
+							},
+							resourceKeyVariablePromptOptionDisplay: &schema.Schema{
+								Type:        schema.TypeString,
+								Optional:    true,
+								Default:     "",
+								Description: "The option's display name (if not specified, the value is displayed).",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Convert a "prompt" block's properties to an Octopus variable prompt.
+//
+// Returns nil if no prompt is configured.
+func expandVariablePrompt(value interface{}) (*octopus.VariablePrompt, error) {
+	prompts := value.([]interface{})
+	if len(prompts) == 0 || prompts[0] == nil {
+		return nil, nil
+	}
+	properties := prompts[0].(map[string]interface{})
+
+	controlType := properties[resourceKeyVariablePromptControlType].(string)
+	prompt := &octopus.VariablePrompt{
+		Label:       properties[resourceKeyVariablePromptLabel].(string),
+		Description: properties[resourceKeyVariablePromptHelpText].(string),
+		Required:    properties[resourceKeyVariablePromptRequired].(bool),
+		DisplaySettings: map[string]string{
+			promptDisplaySettingControlType: controlType,
+		},
+	}
+
+	selectOptions := properties[resourceKeyVariablePromptSelectOptions].([]interface{})
+	if len(selectOptions) > 0 && controlType != promptControlTypeSelect {
+		return nil, fmt.Errorf("Prompt options can only be specified when the prompt's control type is '%s'.", promptControlTypeSelect)
+	}
+
+	optionLines := make([]string, len(selectOptions))
+	for index, selectOption := range selectOptions {
+		optionProperties := selectOption.(map[string]interface{})
+
+		optionValue := optionProperties[resourceKeyVariablePromptOptionValue].(string)
+		optionLine := optionValue
+		optionDisplayName := optionProperties[resourceKeyVariablePromptOptionDisplay].(string)
+		if !isEmpty(optionDisplayName) {
+			optionLine += "|" + optionDisplayName
+		}
+
+		optionLines[index] = optionLine
+	}
+	if controlType == promptControlTypeSelect {
+		prompt.DisplaySettings[promptDisplaySettingSelectOptions] = strings.Join(optionLines, "\n")
+	}
+
+	return prompt, nil
+}
+
+// Convert an Octopus variable prompt to the properties of a "prompt" block.
+func flattenVariablePrompt(prompt *octopus.VariablePrompt) []interface{} {
+	if prompt == nil {
+		return []interface{}{}
+	}
+
+	controlType := prompt.DisplaySettings[promptDisplaySettingControlType]
+	if isEmpty(controlType) {
+		controlType = promptControlTypeSingleLineText
+	}
+
+	selectOptions := make([]interface{}, 0)
+	for _, optionLine := range strings.Split(prompt.DisplaySettings[promptDisplaySettingSelectOptions], "\n") {
+		optionLine = strings.TrimSpace(optionLine)
+		if isEmpty(optionLine) {
+			continue
+		}
+
+		optionValue := optionLine
+		optionDisplayName := ""
+		if separatorIndex := strings.Index(optionLine, "|"); separatorIndex != -1 {
+			optionValue = optionLine[:separatorIndex]
+			optionDisplayName = optionLine[separatorIndex+1:]
+		}
+
+		selectOptions = append(selectOptions, map[string]interface{}{
+			resourceKeyVariablePromptOptionValue:   optionValue,
+			resourceKeyVariablePromptOptionDisplay: optionDisplayName,
+		})
+	}
+
+	return []interface{}{
+		map[string]interface{}{
+			resourceKeyVariablePromptLabel:         prompt.Label,
+			resourceKeyVariablePromptHelpText:      prompt.Description,
+			resourceKeyVariablePromptRequired:      prompt.Required,
+			resourceKeyVariablePromptControlType:   controlType,
+			resourceKeyVariablePromptSelectOptions: selectOptions,
+		},
+	}
+}
+
+// Validate the control type for a prompted variable.
+func validatePromptControlType(value interface{}, key string) (warnings []string, errors []error) {
+	controlType := value.(string)
+	switch controlType {
+	case promptControlTypeSingleLineText, promptControlTypeMultiLineText, promptControlTypeCheckbox, promptControlTypeSelect:
+	default:
+		errors = append(errors, fmt.Errorf("Unsupported prompt control type '%s' for property '%s'.", controlType, key))
+	}
+
+	return
+}
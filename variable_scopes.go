@@ -0,0 +1,119 @@
+package main
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+	"octopus"
+	"sort"
+)
+
+// Get the variable scopes configured for a variable resource or data-source.
+func getVariableScopes(data *schema.ResourceData) octopus.VariableScopes {
+	propertyHelper := propertyHelper(data)
+
+	return octopus.VariableScopes{
+		Environments:  propertyHelper.GetStringList(resourceKeyVariableEnvironments),
+		Roles:         propertyHelper.GetStringList(resourceKeyVariableRoles),
+		Machines:      propertyHelper.GetStringList(resourceKeyVariableMachines),
+		Actions:       propertyHelper.GetStringList(resourceKeyVariableActions),
+		Channels:      propertyHelper.GetStringList(resourceKeyVariableChannels),
+		TenantTags:    propertyHelper.GetStringList(resourceKeyVariableTenantTags),
+		ProcessOwners: propertyHelper.GetStringList(resourceKeyVariableProcesses),
+	}
+}
+
+// Update the variable scopes for a variable resource or data-source.
+func setVariableScopes(data *schema.ResourceData, scopes octopus.VariableScopes) {
+	propertyHelper := propertyHelper(data)
+
+	propertyHelper.SetStringList(resourceKeyVariableEnvironments, scopes.Environments)
+	propertyHelper.SetStringList(resourceKeyVariableRoles, scopes.Roles)
+	propertyHelper.SetStringList(resourceKeyVariableMachines, scopes.Machines)
+	propertyHelper.SetStringList(resourceKeyVariableActions, scopes.Actions)
+	propertyHelper.SetStringList(resourceKeyVariableChannels, scopes.Channels)
+	propertyHelper.SetStringList(resourceKeyVariableTenantTags, scopes.TenantTags)
+	propertyHelper.SetStringList(resourceKeyVariableProcesses, scopes.ProcessOwners)
+}
+
+// Determine whether the variable scope properties of a variable resource have changed.
+func hasVariableScopeChange(data *schema.ResourceData) bool {
+	return data.HasChange(resourceKeyVariableEnvironments) ||
+		data.HasChange(resourceKeyVariableRoles) ||
+		data.HasChange(resourceKeyVariableMachines) ||
+		data.HasChange(resourceKeyVariableActions) ||
+		data.HasChange(resourceKeyVariableChannels) ||
+		data.HasChange(resourceKeyVariableTenantTags) ||
+		data.HasChange(resourceKeyVariableProcesses)
+}
+
+// Find all variables in a variable set with the specified name whose scopes exactly match the specified scopes.
+//
+// All scope dimensions (environments, roles, machines, actions, channels, tenant tags, and processes) are compared, ignoring the order of their elements.
+func findVariablesByNameAndScopes(variableSet *octopus.VariableSet, name string, scopes octopus.VariableScopes) (matchingVariables []octopus.Variable) {
+	for _, variable := range variableSet.Variables {
+		if variable.Name == name && variableScopesMatch(variable.Scope, scopes) {
+			matchingVariables = append(matchingVariables, variable)
+		}
+	}
+
+	return
+}
+
+// Find all variables in a variable set with the specified name whose scopes match the specified scopes in each scope dimension that is specified (i.e. not empty).
+//
+// Scope dimensions that are not specified are ignored (so if no scopes are specified, every variable with the specified name matches).
+func findVariablesByNameAndSpecifiedScopes(variableSet *octopus.VariableSet, name string, scopes octopus.VariableScopes) (matchingVariables []octopus.Variable) {
+	for _, variable := range variableSet.Variables {
+		if variable.Name == name && variableScopesMatchSpecified(variable.Scope, scopes) {
+			matchingVariables = append(matchingVariables, variable)
+		}
+	}
+
+	return
+}
+
+// Determine whether a variable's scopes match the specified scopes in each scope dimension that is specified (i.e. not empty).
+func variableScopesMatchSpecified(variableScopes octopus.VariableScopes, specifiedScopes octopus.VariableScopes) bool {
+	return specifiedStringSetMatches(variableScopes.Environments, specifiedScopes.Environments) &&
+		specifiedStringSetMatches(variableScopes.Roles, specifiedScopes.Roles) &&
+		specifiedStringSetMatches(variableScopes.Machines, specifiedScopes.Machines) &&
+		specifiedStringSetMatches(variableScopes.Actions, specifiedScopes.Actions) &&
+		specifiedStringSetMatches(variableScopes.Channels, specifiedScopes.Channels) &&
+		specifiedStringSetMatches(variableScopes.TenantTags, specifiedScopes.TenantTags) &&
+		specifiedStringSetMatches(variableScopes.ProcessOwners, specifiedScopes.ProcessOwners)
+}
+
+// Determine whether a list of strings contains the same elements as a list of specified strings (ignoring order), or no strings are specified.
+func specifiedStringSetMatches(values []string, specifiedValues []string) bool {
+	return len(specifiedValues) == 0 || stringSetsMatch(values, specifiedValues)
+}
+
+// Determine whether 2 sets of variable scopes are equivalent.
+func variableScopesMatch(scopes1 octopus.VariableScopes, scopes2 octopus.VariableScopes) bool {
+	return stringSetsMatch(scopes1.Environments, scopes2.Environments) &&
+		stringSetsMatch(scopes1.Roles, scopes2.Roles) &&
+		stringSetsMatch(scopes1.Machines, scopes2.Machines) &&
+		stringSetsMatch(scopes1.Actions, scopes2.Actions) &&
+		stringSetsMatch(scopes1.Channels, scopes2.Channels) &&
+		stringSetsMatch(scopes1.TenantTags, scopes2.TenantTags) &&
+		stringSetsMatch(scopes1.ProcessOwners, scopes2.ProcessOwners)
+}
+
+// Determine whether 2 lists of strings contain the same elements (ignoring order).
+func stringSetsMatch(strings1 []string, strings2 []string) bool {
+	if len(strings1) != len(strings2) {
+		return false
+	}
+
+	sorted1 := append([]string{}, strings1...)
+	sort.Strings(sorted1)
+	sorted2 := append([]string{}, strings2...)
+	sort.Strings(sorted2)
+
+	for index := range sorted1 {
+		if sorted1[index] != sorted2[index] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"octopus"
+	"testing"
+)
+
+func TestFindVariablesByNameAndSpecifiedScopes(t *testing.T) {
+	variableSet := &octopus.VariableSet{
+		Variables: []octopus.Variable{
+			{ID: "unscoped", Name: "ConnectionString"},
+			{ID: "test", Name: "ConnectionString", Scope: octopus.VariableScopes{Environments: []string{"Environments-1"}}},
+			{ID: "test-web", Name: "ConnectionString", Scope: octopus.VariableScopes{Environments: []string{"Environments-1"}, Roles: []string{"web"}}},
+			{ID: "production", Name: "ConnectionString", Scope: octopus.VariableScopes{Environments: []string{"Environments-2", "Environments-3"}}},
+			{ID: "other", Name: "Other", Scope: octopus.VariableScopes{Environments: []string{"Environments-1"}}},
+		},
+	}
+
+	testCases := []struct {
+		description string
+		scopes      octopus.VariableScopes
+		expectedIDs []string
+	}{
+		{"no scopes", octopus.VariableScopes{}, []string{"unscoped", "test", "test-web", "production"}},
+		{"environment", octopus.VariableScopes{Environments: []string{"Environments-1"}}, []string{"test", "test-web"}},
+		{"environment and role", octopus.VariableScopes{Environments: []string{"Environments-1"}, Roles: []string{"web"}}, []string{"test-web"}},
+		{"role", octopus.VariableScopes{Roles: []string{"web"}}, []string{"test-web"}},
+		{"environments in any order", octopus.VariableScopes{Environments: []string{"Environments-3", "Environments-2"}}, []string{"production"}},
+		{"subset of environments", octopus.VariableScopes{Environments: []string{"Environments-2"}}, nil},
+		{"unknown channel", octopus.VariableScopes{Channels: []string{"Channels-1"}}, nil},
+	}
+
+	for _, testCase := range testCases {
+		matchingVariables := findVariablesByNameAndSpecifiedScopes(variableSet, "ConnectionString", testCase.scopes)
+
+		var actualIDs []string
+		for _, variable := range matchingVariables {
+			actualIDs = append(actualIDs, variable.ID)
+		}
+		if !stringSetsMatch(actualIDs, testCase.expectedIDs) {
+			t.Errorf("%s: expected variables %v, but found %v.", testCase.description, testCase.expectedIDs, actualIDs)
+		}
+	}
+}
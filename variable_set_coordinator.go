@@ -0,0 +1,182 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"octopus"
+	"sync"
+	"time"
+)
+
+const (
+	// The maximum number of times a batch of variable set changes will be attempted.
+	variableSetUpdateMaxAttempts = 5
+
+	// The delay between attempts to apply a batch of variable set changes.
+	variableSetUpdateRetryDelay = 2 * time.Second
+)
+
+// variableSetMutation represents a change to a variable set.
+//
+// Returns true if the variable set was modified (and therefore needs to be written back to Octopus).
+// Mutations may be applied more than once (to a freshly-retrieved variable set) if the update conflicts with another change.
+type variableSetMutation func(variableSet *octopus.VariableSet) (modified bool, err error)
+
+// variableSetCoordinator serialises read-modify-write operations on variable sets.
+//
+// Mutations targeting the same owner that arrive while a write is in progress are queued up and then applied together as a single update.
+type variableSetCoordinator struct {
+	stateLock *sync.Mutex
+	owners    map[variableOwner]*variableSetWriter
+
+	// The delay between attempts to apply a batch of variable set changes.
+	retryDelay time.Duration
+}
+
+// variableSetWriter tracks writes to the variable set for a single owner.
+type variableSetWriter struct {
+	// Held while a batch of mutations is being written to the owner's variable set.
+	writeLock *sync.Mutex
+
+	// The batch that new mutations will join (guarded by variableSetCoordinator.stateLock).
+	pending *variableSetBatch
+}
+
+// variableSetBatch is a group of mutations that will be applied to a variable set in a single update.
+type variableSetBatch struct {
+	mutations []variableSetMutation
+	errors    []error
+	result    *octopus.VariableSet
+	done      chan struct{}
+}
+
+func newVariableSetCoordinator() *variableSetCoordinator {
+	return &variableSetCoordinator{
+		stateLock:  &sync.Mutex{},
+		owners:     make(map[variableOwner]*variableSetWriter),
+		retryDelay: variableSetUpdateRetryDelay,
+	}
+}
+
+// Update applies a mutation to the variable set belonging to the specified owner.
+//
+// The client must target the owner's space. Returns the updated variable set.
+func (coordinator *variableSetCoordinator) Update(client *octopus.Client, owner variableOwner, mutation variableSetMutation) (*octopus.VariableSet, error) {
+	writer, batch, index := coordinator.enqueue(owner, mutation)
+
+	writer.writeLock.Lock()
+	defer writer.writeLock.Unlock()
+
+	select {
+	case <-batch.done:
+		// Our batch was written by whoever held the lock before us.
+	default:
+		coordinator.stateLock.Lock()
+		if writer.pending == batch {
+			writer.pending = nil // Subsequent mutations will form a new batch.
+		}
+		coordinator.stateLock.Unlock()
+
+		coordinator.apply(client, owner, batch)
+	}
+
+	return batch.result, batch.errors[index]
+}
+
+// Add a mutation to the pending batch for the specified owner.
+func (coordinator *variableSetCoordinator) enqueue(owner variableOwner, mutation variableSetMutation) (writer *variableSetWriter, batch *variableSetBatch, index int) {
+	coordinator.stateLock.Lock()
+	defer coordinator.stateLock.Unlock()
+
+	writer, ok := coordinator.owners[owner]
+	if !ok {
+		writer = &variableSetWriter{
+			writeLock: &sync.Mutex{},
+		}
+		coordinator.owners[owner] = writer
+	}
+
+	if writer.pending == nil {
+		writer.pending = &variableSetBatch{
+			done: make(chan struct{}),
+		}
+	}
+	batch = writer.pending
+
+	index = len(batch.mutations)
+	batch.mutations = append(batch.mutations, mutation)
+	batch.errors = append(batch.errors, nil)
+
+	return
+}
+
+// Apply a batch of mutations to the owner's variable set, retrying if the update conflicts with a change made elsewhere.
+func (coordinator *variableSetCoordinator) apply(client *octopus.Client, owner variableOwner, batch *variableSetBatch) {
+	defer close(batch.done)
+
+	log.Printf("Applying %d change(s) to variable set for %s.", len(batch.mutations), owner)
+
+	var err error
+	for attempt := 1; attempt <= variableSetUpdateMaxAttempts; attempt++ {
+		batch.result, err = coordinator.applyOnce(client, owner, batch)
+		if err == nil {
+			return
+		}
+		if !isVariableSetConflict(err) {
+			break
+		}
+
+		log.Printf("Variable set for %s was modified by another party (attempt %d of %d); will retry after %s.", owner, attempt, variableSetUpdateMaxAttempts, coordinator.retryDelay)
+		time.Sleep(coordinator.retryDelay)
+	}
+
+	for index := range batch.errors {
+		if batch.errors[index] == nil {
+			batch.errors[index] = err
+		}
+	}
+}
+
+// Retrieve the owner's variable set, apply the batch's mutations, and write the variable set back (if required).
+func (coordinator *variableSetCoordinator) applyOnce(client *octopus.Client, owner variableOwner, batch *variableSetBatch) (*octopus.VariableSet, error) {
+	variableSet, err := owner.GetVariableSet(client)
+	if err != nil {
+		return nil, fmt.Errorf("Error retrieving variable set for %s: %s", owner, err.Error())
+	}
+	if variableSet == nil {
+		return nil, &variableSetNotFoundError{owner}
+	}
+
+	modified := false
+	for index, mutation := range batch.mutations {
+		var mutationModified bool
+		mutationModified, batch.errors[index] = mutation(variableSet)
+		if batch.errors[index] == nil {
+			modified = modified || mutationModified
+		}
+	}
+	if !modified {
+		return variableSet, nil
+	}
+
+	return client.UpdateVariableSet(variableSet)
+}
+
+// variableSetNotFoundError is returned when the variable set for an owner cannot be found.
+type variableSetNotFoundError struct {
+	Owner variableOwner
+}
+
+func (err *variableSetNotFoundError) Error() string {
+	return fmt.Sprintf("Cannot find variable set for %s", err.Owner)
+}
+
+// Determine whether an error indicates that a variable set update was rejected because the variable set had been modified since it was retrieved.
+//
+// Octopus rejects updates whose Version does not match the current version of the variable set.
+func isVariableSetConflict(err error) bool {
+	apiError, ok := err.(*octopus.APIError)
+
+	return ok && apiError.StatusCode == http.StatusConflict
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"octopus"
+	"sync"
+	"testing"
+	"time"
+)
+
+var testVariableSetOwner = variableOwner{
+	ProjectID: "Projects-1",
+}
+
+// testVariableSetServer serves a single project's variable set, rejecting updates whose Version does not match (and, optionally, the first few updates regardless).
+type testVariableSetServer struct {
+	*httptest.Server
+
+	stateLock   *sync.Mutex
+	variableSet octopus.VariableSet
+	conflicts   int
+	updates     int
+}
+
+func newTestVariableSetServer(t *testing.T, conflicts int) *testVariableSetServer {
+	server := &testVariableSetServer{
+		stateLock: &sync.Mutex{},
+		variableSet: octopus.VariableSet{
+			ID:        "variableset-Projects-1",
+			OwnerID:   "Projects-1",
+			Version:   1,
+			Variables: []octopus.Variable{},
+		},
+		conflicts: conflicts,
+	}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+		server.stateLock.Lock()
+		defer server.stateLock.Unlock()
+
+		switch request.Method + " " + request.URL.Path {
+		case "GET /api/projects/Projects-1":
+			response.Write([]byte(`{"Id":"Projects-1","VariableSetId":"variableset-Projects-1"}`))
+		case "GET /api/variables/variableset-Projects-1":
+			json.NewEncoder(response).Encode(server.variableSet)
+		case "PUT /api/variables/variableset-Projects-1":
+			body, _ := ioutil.ReadAll(request.Body)
+
+			var variableSet octopus.VariableSet
+			err := json.Unmarshal(body, &variableSet)
+			if err != nil {
+				t.Errorf("Unable to parse variable set: %s", err.Error())
+				response.WriteHeader(http.StatusBadRequest)
+
+				return
+			}
+
+			if server.conflicts > 0 || variableSet.Version != server.variableSet.Version {
+				server.conflicts--
+				response.WriteHeader(http.StatusConflict)
+				response.Write([]byte(`{"ErrorMessage":"The variable set has been modified by another user."}`))
+
+				return
+			}
+
+			server.updates++
+			variableSet.Version++
+			server.variableSet = variableSet
+			json.NewEncoder(response).Encode(server.variableSet)
+		default:
+			t.Errorf("Unexpected request %s %s.", request.Method, request.URL.Path)
+			response.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	return server
+}
+
+// The number of updates accepted by the server.
+func (server *testVariableSetServer) Updates() int {
+	server.stateLock.Lock()
+	defer server.stateLock.Unlock()
+
+	return server.updates
+}
+
+// The names of the variables in the server's variable set.
+func (server *testVariableSetServer) VariableNames() map[string]bool {
+	server.stateLock.Lock()
+	defer server.stateLock.Unlock()
+
+	names := make(map[string]bool)
+	for _, variable := range server.variableSet.Variables {
+		names[variable.Name] = true
+	}
+
+	return names
+}
+
+func newTestVariableSetClient(t *testing.T, server *testVariableSetServer) *octopus.Client {
+	client, err := octopus.NewClient(server.URL, server.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return client
+}
+
+func newTestVariableSetCoordinator() *variableSetCoordinator {
+	coordinator := newVariableSetCoordinator()
+	coordinator.retryDelay = 0
+
+	return coordinator
+}
+
+// Create a mutation that adds a variable.
+func addTestVariable(name string) variableSetMutation {
+	return func(variableSet *octopus.VariableSet) (bool, error) {
+		variableSet.Variables = append(variableSet.Variables, octopus.Variable{
+			Name:  name,
+			Value: "value",
+			Type:  "String",
+		})
+
+		return true, nil
+	}
+}
+
+// The number of mutations waiting to join the next batch for the specified owner.
+func (coordinator *variableSetCoordinator) pendingMutations(owner variableOwner) int {
+	coordinator.stateLock.Lock()
+	defer coordinator.stateLock.Unlock()
+
+	writer, ok := coordinator.owners[owner]
+	if !ok || writer.pending == nil {
+		return 0
+	}
+
+	return len(writer.pending.mutations)
+}
+
+func TestVariableSetCoordinatorBatchesConcurrentMutations(t *testing.T) {
+	server := newTestVariableSetServer(t, 0)
+	defer server.Close()
+
+	client := newTestVariableSetClient(t, server)
+	coordinator := newTestVariableSetCoordinator()
+
+	// Hold up the first write so that the other mutations queue up behind it.
+	firstMutationStarted := make(chan struct{})
+	releaseFirstMutation := make(chan struct{})
+	firstMutation := func(variableSet *octopus.VariableSet) (bool, error) {
+		close(firstMutationStarted)
+		<-releaseFirstMutation
+
+		return addTestVariable("First")(variableSet)
+	}
+
+	names := []string{"First", "Second", "Third", "Fourth"}
+	errors := make([]error, len(names))
+	waitGroup := &sync.WaitGroup{}
+	waitGroup.Add(len(names))
+	go func() {
+		defer waitGroup.Done()
+		_, errors[0] = coordinator.Update(client, testVariableSetOwner, firstMutation)
+	}()
+	<-firstMutationStarted
+
+	for index := 1; index < len(names); index++ {
+		go func(index int) {
+			defer waitGroup.Done()
+			_, errors[index] = coordinator.Update(client, testVariableSetOwner, addTestVariable(names[index]))
+		}(index)
+	}
+	for coordinator.pendingMutations(testVariableSetOwner) < len(names)-1 {
+		time.Sleep(time.Millisecond)
+	}
+	close(releaseFirstMutation)
+	waitGroup.Wait()
+
+	for index, err := range errors {
+		if err != nil {
+			t.Fatalf("Mutation %d failed: %s", index+1, err.Error())
+		}
+	}
+	if server.Updates() != 2 {
+		t.Fatalf("Expected 2 updates (the first mutation, then the others as a single batch), but found %d.", server.Updates())
+	}
+	variableNames := server.VariableNames()
+	for _, name := range names {
+		if !variableNames[name] {
+			t.Fatalf("Expected variable '%s', but found %v.", name, variableNames)
+		}
+	}
+}
+
+func TestVariableSetCoordinatorRetriesAfterConflict(t *testing.T) {
+	server := newTestVariableSetServer(t, 2)
+	defer server.Close()
+
+	client := newTestVariableSetClient(t, server)
+	coordinator := newTestVariableSetCoordinator()
+
+	attempts := 0
+	mutation := func(variableSet *octopus.VariableSet) (bool, error) {
+		attempts++
+
+		return addTestVariable("Retried")(variableSet)
+	}
+
+	variableSet, err := coordinator.Update(client, testVariableSetOwner, mutation)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attempts != 3 {
+		t.Fatalf("Expected the mutation to be applied 3 times, but found %d.", attempts)
+	}
+	if len(variableSet.Variables) != 1 || variableSet.Variables[0].Name != "Retried" {
+		t.Fatalf("Unexpected variables: %#v.", variableSet.Variables)
+	}
+}
+
+func TestVariableSetCoordinatorGivesUpAfterMaxAttempts(t *testing.T) {
+	server := newTestVariableSetServer(t, variableSetUpdateMaxAttempts)
+	defer server.Close()
+
+	client := newTestVariableSetClient(t, server)
+	coordinator := newTestVariableSetCoordinator()
+
+	_, err := coordinator.Update(client, testVariableSetOwner, addTestVariable("Conflicted"))
+	if !isVariableSetConflict(err) {
+		t.Fatalf("Expected a conflict error, but found %#v.", err)
+	}
+	if server.Updates() != 0 {
+		t.Fatalf("Expected no updates, but found %d.", server.Updates())
+	}
+}
+
+func TestIsVariableSetConflict(t *testing.T) {
+	testCases := []struct {
+		err      error
+		expected bool
+	}{
+		{&octopus.APIError{StatusCode: http.StatusConflict}, true},
+		{&octopus.APIError{StatusCode: http.StatusBadRequest, Message: "Conflict between variable scopes."}, false},
+		{&variableSetNotFoundError{testVariableSetOwner}, false},
+	}
+
+	for _, testCase := range testCases {
+		actual := isVariableSetConflict(testCase.err)
+		if actual != testCase.expected {
+			t.Errorf("isVariableSetConflict(%#v) returned %t; expected %t.", testCase.err, actual, testCase.expected)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// A NuGet / SemVer version (e.g. "1.2", "1.2.3-beta.1", or "1.2.3.4+build").
+var versionPattern = regexp.MustCompile(`^(\d+(?:\.\d+){0,3})(?:-([0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*))?(?:\+[0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*)?$`)
+
+// Validate a NuGet version range (e.g. "1.0", "[1.0]", "[1.0,2.0)", "(,2.0]").
+func validateVersionRange(value interface{}, key string) (warnings []string, errors []error) {
+	err := parseVersionRange(value.(string))
+	if err != nil {
+		errors = append(errors, fmt.Errorf("Invalid version range for property '%s': %s", key, err.Error()))
+	}
+
+	return
+}
+
+// Parse a NuGet version range.
+//
+// See https://docs.microsoft.com/en-us/nuget/concepts/package-versioning#version-ranges for the supported syntax.
+func parseVersionRange(versionRange string) error {
+	versionRange = strings.TrimSpace(versionRange)
+	if isEmpty(versionRange) {
+		return fmt.Errorf("The version range cannot be empty.")
+	}
+
+	// A bare version is a minimum version (inclusive).
+	if !strings.ContainsAny(versionRange[:1], "[(") {
+		return validateVersion(versionRange)
+	}
+
+	minInclusive := versionRange[0] == '['
+	lastChar := versionRange[len(versionRange)-1]
+	if lastChar != ']' && lastChar != ')' {
+		return fmt.Errorf("'%s' must end with ']' or ')'.", versionRange)
+	}
+	maxInclusive := lastChar == ']'
+
+	bounds := strings.Split(versionRange[1:len(versionRange)-1], ",")
+	switch len(bounds) {
+	case 1:
+		// An exact version ("[1.0]").
+		if !minInclusive || !maxInclusive {
+			return fmt.Errorf("'%s' must use '[' and ']' to specify an exact version.", versionRange)
+		}
+
+		return validateVersion(strings.TrimSpace(bounds[0]))
+	case 2:
+		minVersion := strings.TrimSpace(bounds[0])
+		maxVersion := strings.TrimSpace(bounds[1])
+		if isEmpty(minVersion) && isEmpty(maxVersion) {
+			return fmt.Errorf("'%s' must specify a minimum and / or maximum version.", versionRange)
+		}
+		if !isEmpty(minVersion) {
+			err := validateVersion(minVersion)
+			if err != nil {
+				return err
+			}
+		}
+		if !isEmpty(maxVersion) {
+			err := validateVersion(maxVersion)
+			if err != nil {
+				return err
+			}
+		}
+		if !isEmpty(minVersion) && !isEmpty(maxVersion) {
+			comparison := compareVersions(minVersion, maxVersion)
+			if comparison > 0 || (comparison == 0 && !(minInclusive && maxInclusive)) {
+				return fmt.Errorf("'%s' does not match any versions (its minimum version is not less than its maximum version).", versionRange)
+			}
+		}
+
+		return nil
+	default:
+		return fmt.Errorf("'%s' must contain at most one ','.", versionRange)
+	}
+}
+
+// Validate a NuGet / SemVer version.
+func validateVersion(version string) error {
+	if !versionPattern.MatchString(version) {
+		return fmt.Errorf("'%s' is not a valid version.", version)
+	}
+
+	return nil
+}
+
+// Compare two versions using SemVer precedence (build metadata is ignored).
+//
+// Returns -1, 0, or 1 if the first version is less than, equal to, or greater than the second version.
+func compareVersions(version1 string, version2 string) int {
+	match1 := versionPattern.FindStringSubmatch(version1)
+	match2 := versionPattern.FindStringSubmatch(version2)
+
+	comparison := compareVersionNumbers(match1[1], match2[1])
+	if comparison != 0 {
+		return comparison
+	}
+
+	return compareReleaseLabels(match1[2], match2[2])
+}
+
+// Compare the numeric parts of two versions (e.g. "1.2.3").
+func compareVersionNumbers(numbers1 string, numbers2 string) int {
+	parts1 := strings.Split(numbers1, ".")
+	parts2 := strings.Split(numbers2, ".")
+
+	for index := 0; index < len(parts1) || index < len(parts2); index++ {
+		var number1, number2 int
+		if index < len(parts1) {
+			number1, _ = strconv.Atoi(parts1[index])
+		}
+		if index < len(parts2) {
+			number2, _ = strconv.Atoi(parts2[index])
+		}
+
+		comparison := compareInts(number1, number2)
+		if comparison != 0 {
+			return comparison
+		}
+	}
+
+	return 0
+}
+
+// Compare the pre-release labels of two versions with the same numeric parts (e.g. "beta.2").
+//
+// A version without a pre-release label is greater than one with a label. Otherwise, the labels' dot-separated identifiers are compared in turn;
+// numeric identifiers are compared numerically and are less than alphanumeric identifiers, which are compared case-insensitively (as NuGet does).
+func compareReleaseLabels(label1 string, label2 string) int {
+	if label1 == label2 {
+		return 0
+	}
+	if isEmpty(label1) {
+		return 1
+	}
+	if isEmpty(label2) {
+		return -1
+	}
+
+	identifiers1 := strings.Split(label1, ".")
+	identifiers2 := strings.Split(label2, ".")
+	for index := 0; index < len(identifiers1) && index < len(identifiers2); index++ {
+		comparison := compareReleaseLabelIdentifiers(identifiers1[index], identifiers2[index])
+		if comparison != 0 {
+			return comparison
+		}
+	}
+
+	return compareInts(len(identifiers1), len(identifiers2))
+}
+
+// Compare a single identifier from each of two pre-release labels.
+func compareReleaseLabelIdentifiers(identifier1 string, identifier2 string) int {
+	number1, err1 := strconv.Atoi(identifier1)
+	number2, err2 := strconv.Atoi(identifier2)
+
+	switch {
+	case err1 == nil && err2 == nil:
+		return compareInts(number1, number2)
+	case err1 == nil:
+		return -1
+	case err2 == nil:
+		return 1
+	default:
+		return strings.Compare(strings.ToLower(identifier1), strings.ToLower(identifier2))
+	}
+}
+
+func compareInts(value1 int, value2 int) int {
+	if value1 < value2 {
+		return -1
+	} else if value1 > value2 {
+		return 1
+	}
+
+	return 0
+}
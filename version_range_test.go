@@ -0,0 +1,82 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestParseVersionRange(t *testing.T) {
+	testCases := []struct {
+		versionRange string
+		valid        bool
+	}{
+		// Valid ranges.
+		{"1.0", true},
+		{"[1.0]", true},
+		{"[1.0,2.0)", true},
+		{"(1.0,2.0]", true},
+		{"(,2.0]", true},
+		{"[1.0,)", true},
+		{"[1.0,1.0]", true},
+		{" [1.2.3.4, 2.0.0+build] ", true},
+
+		// Invalid ranges.
+		{"", false},
+		{"one", false},
+		{"[1.0", false},
+		{"(1.0)", false},
+		{"[,]", false},
+		{"[1.0,2.0,3.0]", false},
+		{"[2.0,1.0]", false},
+		{"[1.0,1.0)", false},
+		{"(1.0,1.0]", false},
+
+		// Pre-release versions.
+		{"[1.0.0-alpha,1.0.0)", true},
+		{"[1.0.0-0,1.0.0)", true},
+		{"[1.0.0-alpha,1.0.0-beta)", true},
+		{"[1.0.0-alpha,1.0.0-alpha.1)", true},
+		{"[1.0.0-alpha.1,1.0.0-alpha.beta)", true},
+		{"[1.0.0-beta.2,1.0.0-beta.11)", true},
+		{"[1.0.0-Beta,1.0.0-beta]", true},
+		{"[1.0.0,1.0.0-alpha)", false},
+		{"[1.0.0-beta,1.0.0-alpha)", false},
+		{"[1.0.0-beta.11,1.0.0-beta.2)", false},
+		{"[1.0.0-alpha,1.0.0-alpha)", false},
+		{"[1.0.0-alpha,)", true},
+		{"[1.0.0-,2.0.0)", false},
+	}
+
+	for _, testCase := range testCases {
+		err := parseVersionRange(testCase.versionRange)
+		if testCase.valid && err != nil {
+			t.Errorf("Expected '%s' to be valid, but found error: %s", testCase.versionRange, err.Error())
+		} else if !testCase.valid && err == nil {
+			t.Errorf("Expected '%s' to be invalid.", testCase.versionRange)
+		}
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	testCases := []struct {
+		version1 string
+		version2 string
+		expected int
+	}{
+		{"1.0", "1.0.0.0", 0},
+		{"1.0.1", "1.0", 1},
+		{"1.9", "1.10", -1},
+		{"1.0.0+build.1", "1.0.0+build.2", 0},
+		{"1.0.0-alpha", "1.0.0", -1},
+		{"1.0.0-0", "1.0.0-alpha", -1},
+		{"1.0.0-rc.1", "1.0.0-RC.1", 0},
+		{"1.0.0-alpha.1", "1.0.0-alpha", 1},
+		{"1.0.1-alpha", "1.0.0", 1},
+	}
+
+	for _, testCase := range testCases {
+		actual := compareVersions(testCase.version1, testCase.version2)
+		if actual != testCase.expected {
+			t.Errorf("compareVersions('%s', '%s') returned %d; expected %d.", testCase.version1, testCase.version2, actual, testCase.expected)
+		}
+	}
+}